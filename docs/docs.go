@@ -41,8 +41,47 @@ The following table contains some examples of apphub-app-creator.
 | generate | ` + getSingleLine(cmd.GetGenAppExample(5)) + `|
 | generate | ` + getSingleLine(cmd.GetGenAppExample(6)) + `|
 | generate | ` + getSingleLine(cmd.GetGenAppExample(7)) + `|
+| generate | ` + getSingleLine(cmd.GetGenAppExample(8)) + `|
+| generate | ` + getSingleLine(cmd.GetGenAppExample(9)) + `|
+| generate | ` + getSingleLine(cmd.GetGenAppExample(10)) + `|
 | delete   | ` + getSingleLine(cmd.GetDelAppExample(0)) + `|
 | delete   | ` + getSingleLine(cmd.GetDelAppExample(1)) + `|
+| delete   | ` + getSingleLine(cmd.GetDelAppExample(2)) + `|
+| delete   | ` + getSingleLine(cmd.GetDelAppExample(3)) + `|
+| delete   | ` + getSingleLine(cmd.GetDelAppExample(4)) + `|
+| delete   | ` + getSingleLine(cmd.GetDelAppExample(5)) + `|
+| restore  | ` + getSingleLine(cmd.GetRestoreAppExample(0)) + `|
+| restore  | ` + getSingleLine(cmd.GetRestoreAppExample(1)) + `|
+| undo     | ` + getSingleLine(cmd.GetUndoExample(0)) + `|
+| undo     | ` + getSingleLine(cmd.GetUndoExample(1)) + `|
+| list     | ` + getSingleLine(cmd.GetListAppExample(0)) + `|
+| list     | ` + getSingleLine(cmd.GetListAppExample(1)) + `|
+| list     | ` + getSingleLine(cmd.GetListAppExample(2)) + `|
+| describe | ` + getSingleLine(cmd.GetDescribeAppExample(0)) + `|
+| describe | ` + getSingleLine(cmd.GetDescribeAppExample(1)) + `|
+| adopt    | ` + getSingleLine(cmd.GetAdoptAppExample(0)) + `|
+| adopt    | ` + getSingleLine(cmd.GetAdoptAppExample(1)) + `|
+| detach   | ` + getSingleLine(cmd.GetDetachExample(0)) + `|
+| detach   | ` + getSingleLine(cmd.GetDetachExample(1)) + `|
+| move     | ` + getSingleLine(cmd.GetMoveExample(0)) + `|
+| move     | ` + getSingleLine(cmd.GetMoveExample(1)) + `|
+| merge    | ` + getSingleLine(cmd.GetMergeAppExample(0)) + `|
+| merge    | ` + getSingleLine(cmd.GetMergeAppExample(1)) + `|
+| split    | ` + getSingleLine(cmd.GetSplitAppExample(0)) + `|
+| split    | ` + getSingleLine(cmd.GetSplitAppExample(1)) + `|
+| unregistered | ` + getSingleLine(cmd.GetUnregisteredExample(0)) + `|
+| coverage | ` + getSingleLine(cmd.GetCoverageExample(0)) + `|
+| coverage | ` + getSingleLine(cmd.GetCoverageExample(1)) + `|
+| attach-projects | ` + getSingleLine(cmd.GetAttachProjectsExample(0)) + `|
+| attach-projects | ` + getSingleLine(cmd.GetAttachProjectsExample(1)) + `|
+| doctor   | ` + getSingleLine(cmd.GetDoctorExample(0)) + `|
+| doctor   | ` + getSingleLine(cmd.GetDoctorExample(1)) + `|
+| watch    | ` + getSingleLine(cmd.GetWatchExample(0)) + `|
+| watch    | ` + getSingleLine(cmd.GetWatchExample(1)) + `|
+| feed     | ` + getSingleLine(cmd.GetFeedExample(0)) + `|
+| feed     | ` + getSingleLine(cmd.GetFeedExample(1)) + `|
+| serve    | ` + getSingleLine(cmd.GetServeExample(0)) + `|
+| serve    | ` + getSingleLine(cmd.GetServeExample(1)) + `|
 
 
 NOTE: This file is auto-generated during a release. Do not modify.`
@@ -10,7 +10,10 @@ require internal/client v1.0.0 // indirect
 
 replace internal/client => ./internal/client
 
-require internal/cmd v1.0.0
+require (
+	github.com/spf13/cobra v1.10.1
+	internal/cmd v1.0.0
+)
 
 replace internal/cmd => ./internal/cmd
 
@@ -39,7 +42,6 @@ require (
 	github.com/googleapis/gax-go/v2 v2.15.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/russross/blackfriday/v2 v2.1.0 // indirect
-	github.com/spf13/cobra v1.10.1 // indirect
 	github.com/spf13/pflag v1.0.10 // indirect
 	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
 	go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc v0.61.0 // indirect
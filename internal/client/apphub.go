@@ -16,9 +16,9 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"internal/clilog"
-	"regexp"
 	"strings"
 
 	apphub "cloud.google.com/go/apphub/apiv1"
@@ -30,9 +30,44 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// Lookups that succeed without a discovered service or workload return these errors
+var (
+	errServiceNotDiscovered  = errors.New("discovered service not found for URI")
+	errWorkloadNotDiscovered = errors.New("workload not found for URI")
+)
+
+// isNotDiscovered returns true if App Hub has no discovered service or workload for the URI
+func isNotDiscovered(err error) bool {
+	if errors.Is(err, errServiceNotDiscovered) || errors.Is(err, errWorkloadNotDiscovered) {
+		return true
+	}
+	st, ok := status.FromError(err)
+	return ok && st.Code() == codes.NotFound
+}
+
 // lookupDiscoveredService finds a DiscoveredService or Workload resource in App Hub based on its underlying resource URI.
 // The DiscoveredService/Workload represents an existing GCP resource (like a Cloud Run service) that App Hub is aware of.
+// The asset type registry decides how the resource URI is mapped and which locations are tried.
 func lookupDiscoveredServiceOrWorkload(apiclient appHubClient, projectID, location, resourceURI, appHubType string, asset *assetpb.ResourceSearchResult) (string, error) {
+	var (
+		name string
+		err  error
+	)
+
+	fixedResourceURI := fixResourceURI(resourceURI, asset)
+
+	for _, lookupLocation := range getLookupLocations(location, resourceURI, asset) {
+		name, err = lookupDiscoveredName(apiclient, projectID, lookupLocation, fixedResourceURI, appHubType)
+		if st, ok := status.FromError(err); ok && st.Code() == codes.NotFound {
+			continue
+		}
+		break
+	}
+	return name, err
+}
+
+// lookupDiscoveredName looks up a DiscoveredService or Workload in a single location.
+func lookupDiscoveredName(apiclient appHubClient, projectID, location, resourceURI, appHubType string) (string, error) {
 	ctx := context.Background()
 	logger := clilog.GetLogger()
 
@@ -45,18 +80,17 @@ func lookupDiscoveredServiceOrWorkload(apiclient appHubClient, projectID, locati
 
 	switch appHubType {
 	case "discoveredService":
-		fixedResourceURI := fixResourceURI(resourceURI, asset)
 		req := &apphubpb.LookupDiscoveredServiceRequest{
 			Parent: parent,
-			Uri:    fixedResourceURI,
+			Uri:    resourceURI,
 		}
-		logger.Info("Looking up Discovered Service for URI", "parent", parent, "uri", fixedResourceURI)
+		logger.Info("Looking up Discovered Service for URI", "parent", parent, "uri", resourceURI)
 		var response *apphubpb.LookupDiscoveredServiceResponse
 		response, err = apiclient.LookupDiscoveredService(ctx, req)
 		if err == nil {
 			if response.GetDiscoveredService() == nil {
-				logger.Warn("Lookup API succeeded but returned no discovered service", "uri", fixedResourceURI)
-				return "", fmt.Errorf("discovered service not found for URI: %s", fixedResourceURI)
+				logger.Warn("Lookup API succeeded but returned no discovered service", "uri", resourceURI)
+				return "", fmt.Errorf("%w: %s", errServiceNotDiscovered, resourceURI)
 			}
 			name = response.GetDiscoveredService().GetName()
 		}
@@ -72,7 +106,7 @@ func lookupDiscoveredServiceOrWorkload(apiclient appHubClient, projectID, locati
 		if err == nil {
 			if response.GetDiscoveredWorkload() == nil {
 				logger.Warn("Lookup API succeeded but returned no discovered workload", "uri", resourceURI)
-				return "", fmt.Errorf("workload not found for URI: %s", resourceURI)
+				return "", fmt.Errorf("%w: %s", errWorkloadNotDiscovered, resourceURI)
 			}
 			name = response.GetDiscoveredWorkload().GetName()
 		}
@@ -88,11 +122,6 @@ func lookupDiscoveredServiceOrWorkload(apiclient appHubClient, projectID, locati
 					permission = "apphub.discoveredWorkloads.list"
 				}
 				return "", fmt.Errorf("permission denied: ensure the user has the '%s' permission on the project: %w", permission, err)
-			} else if st.Code() == codes.NotFound {
-				// if it is a k8s gateway, try looking again in the global region
-				if strings.Contains(resourceURI, "gateway.networking.k8s.io") {
-					return lookupDiscoveredServiceOrWorkload(apiclient, projectID, "global", resourceURI, appHubType, asset)
-				}
 			}
 			logger.Error("App Hub lookup API failed", "code", st.Code().String(), "error", err)
 			return "", fmt.Errorf("app hub lookup API failed (Code: %s): %w", st.Code().String(), err)
@@ -105,8 +134,10 @@ func lookupDiscoveredServiceOrWorkload(apiclient appHubClient, projectID, locati
 }
 
 // getOrCreateAppHubApplication attempts to retrieve an App Hub application by name.
-// If it does not exist, it creates a new one and waits for the operation to complete.
-func getOrCreateAppHubApplication(apiclient appHubClient, projectID, location, appID string, data []byte) (*apphubpb.Application, error) {
+// If it does not exist, it creates a new one with the provenance marker as its
+// description and waits for the operation to complete. Existing applications
+// without the marker are only returned when unmanaged applications are included.
+func getOrCreateAppHubApplication(apiclient appHubClient, projectID, location, appID string, data []byte, marker string) (*apphubpb.Application, error) {
 	ctx := context.Background()
 
 	logger := clilog.GetLogger()
@@ -126,6 +157,9 @@ func getOrCreateAppHubApplication(apiclient appHubClient, projectID, location, a
 
 	app, err := apiclient.GetApplication(ctx, getApplicationReq)
 	if err == nil {
+		if !isManagedApp(app) && !toolProvenance.includeUnmanaged {
+			return nil, errUnmanaged(applicationName)
+		}
 		logger.Info("Application already exists. Returning existing resource.", "app-name", applicationName)
 		return app, nil
 	}
@@ -155,6 +189,7 @@ func getOrCreateAppHubApplication(apiclient appHubClient, projectID, location, a
 		ApplicationId: appID,
 		Application: &apphubpb.Application{
 			DisplayName: appID,
+			Description: marker,
 			// Set mandatory scope and optional attributes
 			Scope: &apphubpb.Scope{
 				Type: appScope,
@@ -176,6 +211,7 @@ func getOrCreateAppHubApplication(apiclient appHubClient, projectID, location, a
 		return nil, fmt.Errorf("application creation failed during wait: %w", err)
 	}
 
+	recordMutation("CREATE_APPLICATION", createdApp.GetName(), nil, createdApp)
 	logger.Info("Application successfully created.", "app-name", createdApp.Name)
 	return createdApp, nil
 }
@@ -241,12 +277,17 @@ func registerServiceWithApplication(apiclient appHubClient, projectID, location,
 		if err != nil {
 			// Check for ALREADY_EXISTS if the workload is already registered to this app
 			if st, ok := status.FromError(err); ok && st.Code() == codes.FailedPrecondition {
+				if reassignRegistrations {
+					logger.Info("Service is registered with another application. Reassigning", "service", id, "app-name", appID)
+					return reassignRegistration(apiclient, projectID, location, appID, discoveredName)
+				}
 				logger.Info("Service is already registered with application. Skipping creation", "service", id, "app-name", appID)
 				return nil
 			}
 			return fmt.Errorf("service registration failed during wait: %w", err)
 		}
 
+		recordMutation("CREATE_SERVICE", createdService.GetName(), nil, createdService)
 		logger.Info("Service successfully registered to application.", "service", createdService.Name, "app-name", appID)
 		return nil
 	} else {
@@ -279,20 +320,23 @@ func registerServiceWithApplication(apiclient appHubClient, projectID, location,
 		if err != nil {
 			// Check for ALREADY_EXISTS if the workload is already registered to this app
 			if st, ok := status.FromError(err); ok && st.Code() == codes.FailedPrecondition {
+				if reassignRegistrations {
+					logger.Info("Workload is registered with another application. Reassigning", "workload", id, "app-name", appID)
+					return reassignRegistration(apiclient, projectID, location, appID, discoveredName)
+				}
 				logger.Info("Workload is already registered with application. Skipping creation", "workload", id, "app-name", appID)
 				return nil
 			}
 			return fmt.Errorf("workload registration failed during wait: %w", err)
 		}
 
+		recordMutation("CREATE_WORKLOAD", createdWorkload.GetName(), nil, createdWorkload)
 		logger.Info("Workload successfully registered to application.", "workload", createdWorkload.Name, "app-name", appID)
 		return nil
 	}
 }
 
-func removeAllServices(apiclient appHubClient, projectID, location, appID string) error {
-	const maxConcurrentDeletions = 4
-
+func removeAllServices(apiclient appHubClient, budget deletionBudget, projectID, location, appID string) error {
 	// Use context.Background() as the base context
 	ctx := context.Background()
 	logger := clilog.GetLogger()
@@ -304,15 +348,13 @@ func removeAllServices(apiclient appHubClient, projectID, location, appID string
 		Parent: parent,
 	}
 
+	// The concurrency is limited by the budget shared with every other application
 	g, ctx := errgroup.WithContext(ctx)
 
-	// Set the concurrency limit
-	g.SetLimit(maxConcurrentDeletions)
-
 	// Call the ListServices API
 	listServices := apiclient.ListServices(ctx, reqServices)
 
-	logger.Info("Starting service deletion...", "maxConcurrency", maxConcurrentDeletions)
+	logger.Info("Starting service deletion...", "maxConcurrency", cap(budget))
 
 	for {
 		service, err := listServices.Next()
@@ -326,6 +368,9 @@ func removeAllServices(apiclient appHubClient, projectID, location, appID string
 		serviceCopy := service
 
 		g.Go(func() error {
+			budget.acquire()
+			defer budget.release()
+
 			logger.Info("Starting deletion...", "service", serviceCopy.Name)
 
 			// Construct the DeleteService Request
@@ -344,6 +389,7 @@ func removeAllServices(apiclient appHubClient, projectID, location, appID string
 				return fmt.Errorf("wait for service deletion failed for %s: %w", serviceCopy.Name, err)
 			}
 
+			recordMutation("DELETE_SERVICE", serviceCopy.GetName(), serviceCopy, nil)
 			logger.Info("Service successfully deleted.", "service", serviceCopy.Name)
 			return nil
 		})
@@ -358,9 +404,7 @@ func removeAllServices(apiclient appHubClient, projectID, location, appID string
 	return nil
 }
 
-func removeAllWorkloads(apiclient appHubClient, projectID, location, appID string) error {
-	const maxConcurrentDeletions = 4
-
+func removeAllWorkloads(apiclient appHubClient, budget deletionBudget, projectID, location, appID string) error {
 	// Use context.Background() as the base context
 	ctx := context.Background()
 	logger := clilog.GetLogger()
@@ -372,15 +416,13 @@ func removeAllWorkloads(apiclient appHubClient, projectID, location, appID strin
 		Parent: parent,
 	}
 
+	// The concurrency is limited by the budget shared with every other application
 	g, ctx := errgroup.WithContext(ctx)
 
-	// Set the concurrency limit
-	g.SetLimit(maxConcurrentDeletions)
-
 	// Call the ListWorkloads API
 	listWorkloads := apiclient.ListWorkloads(ctx, reqWorkloads)
 
-	logger.Info("Starting workloads deletion...", "maxConcurrency", maxConcurrentDeletions)
+	logger.Info("Starting workloads deletion...", "maxConcurrency", cap(budget))
 
 	for {
 		workload, err := listWorkloads.Next()
@@ -394,6 +436,9 @@ func removeAllWorkloads(apiclient appHubClient, projectID, location, appID strin
 		workloadCopy := workload
 
 		g.Go(func() error {
+			budget.acquire()
+			defer budget.release()
+
 			logger.Info("Starting deletion...", "workload", workloadCopy.Name)
 
 			// Construct the DeleteWorkload Request
@@ -412,6 +457,7 @@ func removeAllWorkloads(apiclient appHubClient, projectID, location, appID strin
 				return fmt.Errorf("wait for workload deletion failed for %s: %w", workloadCopy.Name, err)
 			}
 
+			recordMutation("DELETE_WORKLOAD", workloadCopy.GetName(), workloadCopy, nil)
 			logger.Info("Workload successfully deleted.", "service", workloadCopy.Name)
 			return nil
 		})
@@ -425,7 +471,7 @@ func removeAllWorkloads(apiclient appHubClient, projectID, location, appID strin
 	return nil
 }
 
-func deleteApp(apiclient appHubClient, projectID, location, appID string) error {
+func deleteApp(apiclient appHubClient, budget deletionBudget, projectID, location, appID string) error {
 	var err error
 
 	ctx := context.Background()
@@ -433,13 +479,13 @@ func deleteApp(apiclient appHubClient, projectID, location, appID string) error
 	logger := clilog.GetLogger()
 
 	logger.Info("Removing all services from application", "app-name", appID)
-	err = removeAllServices(apiclient, projectID, location, appID)
+	err = removeAllServices(apiclient, budget, projectID, location, appID)
 	if err != nil {
 		return fmt.Errorf("failed to remove all services: %w", err)
 	}
 
 	logger.Info("Removing all workloads from application", "app-name", appID)
-	err = removeAllWorkloads(apiclient, projectID, location, appID)
+	err = removeAllWorkloads(apiclient, budget, projectID, location, appID)
 	if err != nil {
 		return fmt.Errorf("failed to remove all workloads: %w", err)
 	}
@@ -451,6 +497,15 @@ func deleteApp(apiclient appHubClient, projectID, location, appID string) error
 		Name: parent,
 	}
 
+	// Keep the definition of the application for the journal
+	app, err := apiclient.GetApplication(ctx, &apphubpb.GetApplicationRequest{Name: parent})
+	if err != nil {
+		return fmt.Errorf("failed to get application: %w", err)
+	}
+
+	budget.acquire()
+	defer budget.release()
+
 	// Delete the application
 	op, err := apiclient.DeleteApplication(ctx, req)
 	if err != nil {
@@ -461,6 +516,7 @@ func deleteApp(apiclient appHubClient, projectID, location, appID string) error
 	if err != nil {
 		return fmt.Errorf("application deletion failed during wait: %w", err)
 	}
+	recordMutation("DELETE_APPLICATION", parent, app, nil)
 	logger.Info("Application successfully deleted", "app-name", appID)
 
 	return nil
@@ -480,24 +536,6 @@ func closeAppHubClient(apiclient appHubClient) {
 	apiclient.Close()
 }
 
-func fixResourceURI(resourceURI string, asset *assetpb.ResourceSearchResult) string {
-	if asset == nil {
-		return resourceURI
-	}
-	if asset.AssetType == "sqladmin.googleapis.com/Instance" {
-		// step 1 replace the URI
-		resourceURI = strings.Replace(resourceURI, "cloudsql.googleapis.com", "sqladmin.googleapis.com", 1)
-
-		// step 2 get the project number
-		projectNumber := strings.Split(asset.Project, "/")[1]
-
-		// step 3 replace project id with project number
-		re := regexp.MustCompile(`(projects/)([^/]+)(/instances/)`)
-		resourceURI = re.ReplaceAllString(resourceURI, fmt.Sprintf("${1}%s${3}", projectNumber))
-	}
-	return resourceURI
-}
-
 // truncateName truncates the display name to a maximum of 63 runes (characters).
 func truncateName(s string) string {
 	const maxLen = 63
@@ -20,7 +20,9 @@ import (
 	"testing"
 
 	apphub "cloud.google.com/go/apphub/apiv1"
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
 	"github.com/googleapis/gax-go/v2"
+	locationpb "google.golang.org/genproto/googleapis/cloud/location"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -34,8 +36,12 @@ type mockAppHubClient struct {
 	lookupDiscoveredWorkloadFunc func(ctx context.Context, req *apphubpb.LookupDiscoveredWorkloadRequest, opts ...gax.CallOption) (*apphubpb.LookupDiscoveredWorkloadResponse, error)
 	getApplicationFunc           func(ctx context.Context, req *apphubpb.GetApplicationRequest, opts ...gax.CallOption) (*apphubpb.Application, error)
 	createApplicationFunc        func(ctx context.Context, req *apphubpb.CreateApplicationRequest, opts ...gax.CallOption) (*apphub.CreateApplicationOperation, error)
+	updateApplicationFunc        func(ctx context.Context, req *apphubpb.UpdateApplicationRequest, opts ...gax.CallOption) (*apphub.UpdateApplicationOperation, error)
 	createServiceFunc            func(ctx context.Context, req *apphubpb.CreateServiceRequest, opts ...gax.CallOption) (*apphub.CreateServiceOperation, error)
 	createWorkloadFunc           func(ctx context.Context, req *apphubpb.CreateWorkloadRequest, opts ...gax.CallOption) (*apphub.CreateWorkloadOperation, error)
+	getServiceFunc               func(ctx context.Context, req *apphubpb.GetServiceRequest, opts ...gax.CallOption) (*apphubpb.Service, error)
+	getWorkloadFunc              func(ctx context.Context, req *apphubpb.GetWorkloadRequest, opts ...gax.CallOption) (*apphubpb.Workload, error)
+	lookupAttachmentFunc         func(ctx context.Context, req *apphubpb.LookupServiceProjectAttachmentRequest, opts ...gax.CallOption) (*apphubpb.LookupServiceProjectAttachmentResponse, error)
 }
 
 func (m *mockAppHubClient) LookupDiscoveredService(ctx context.Context, req *apphubpb.LookupDiscoveredServiceRequest, opts ...gax.CallOption) (*apphubpb.LookupDiscoveredServiceResponse, error) {
@@ -46,6 +52,14 @@ func (m *mockAppHubClient) LookupDiscoveredWorkload(ctx context.Context, req *ap
 	return m.lookupDiscoveredWorkloadFunc(ctx, req, opts...)
 }
 
+func (m *mockAppHubClient) ListDiscoveredServices(ctx context.Context, req *apphubpb.ListDiscoveredServicesRequest, opts ...gax.CallOption) *apphub.DiscoveredServiceIterator {
+	return nil
+}
+
+func (m *mockAppHubClient) ListDiscoveredWorkloads(ctx context.Context, req *apphubpb.ListDiscoveredWorkloadsRequest, opts ...gax.CallOption) *apphub.DiscoveredWorkloadIterator {
+	return nil
+}
+
 func (m *mockAppHubClient) GetApplication(ctx context.Context, req *apphubpb.GetApplicationRequest, opts ...gax.CallOption) (*apphubpb.Application, error) {
 	return m.getApplicationFunc(ctx, req, opts...)
 }
@@ -54,6 +68,10 @@ func (m *mockAppHubClient) CreateApplication(ctx context.Context, req *apphubpb.
 	return m.createApplicationFunc(ctx, req, opts...)
 }
 
+func (m *mockAppHubClient) UpdateApplication(ctx context.Context, req *apphubpb.UpdateApplicationRequest, opts ...gax.CallOption) (*apphub.UpdateApplicationOperation, error) {
+	return m.updateApplicationFunc(ctx, req, opts...)
+}
+
 func (m *mockAppHubClient) CreateService(ctx context.Context, req *apphubpb.CreateServiceRequest, opts ...gax.CallOption) (*apphub.CreateServiceOperation, error) {
 	return m.createServiceFunc(ctx, req, opts...)
 }
@@ -62,6 +80,14 @@ func (m *mockAppHubClient) CreateWorkload(ctx context.Context, req *apphubpb.Cre
 	return m.createWorkloadFunc(ctx, req, opts...)
 }
 
+func (m *mockAppHubClient) GetService(ctx context.Context, req *apphubpb.GetServiceRequest, opts ...gax.CallOption) (*apphubpb.Service, error) {
+	return m.getServiceFunc(ctx, req, opts...)
+}
+
+func (m *mockAppHubClient) GetWorkload(ctx context.Context, req *apphubpb.GetWorkloadRequest, opts ...gax.CallOption) (*apphubpb.Workload, error) {
+	return m.getWorkloadFunc(ctx, req, opts...)
+}
+
 func (m *mockAppHubClient) ListServices(ctx context.Context, req *apphubpb.ListServicesRequest, opts ...gax.CallOption) *apphub.ServiceIterator {
 	return nil
 }
@@ -86,6 +112,22 @@ func (m *mockAppHubClient) ListApplications(ctx context.Context, req *apphubpb.L
 	return nil
 }
 
+func (m *mockAppHubClient) ListServiceProjectAttachments(ctx context.Context, req *apphubpb.ListServiceProjectAttachmentsRequest, opts ...gax.CallOption) *apphub.ServiceProjectAttachmentIterator {
+	return nil
+}
+
+func (m *mockAppHubClient) LookupServiceProjectAttachment(ctx context.Context, req *apphubpb.LookupServiceProjectAttachmentRequest, opts ...gax.CallOption) (*apphubpb.LookupServiceProjectAttachmentResponse, error) {
+	return m.lookupAttachmentFunc(ctx, req, opts...)
+}
+
+func (m *mockAppHubClient) CreateServiceProjectAttachment(ctx context.Context, req *apphubpb.CreateServiceProjectAttachmentRequest, opts ...gax.CallOption) (*apphub.CreateServiceProjectAttachmentOperation, error) {
+	return nil, nil
+}
+
+func (m *mockAppHubClient) ListLocations(ctx context.Context, req *locationpb.ListLocationsRequest, opts ...gax.CallOption) *apphub.LocationIterator {
+	return nil
+}
+
 func (m *mockAppHubClient) Close() error {
 	return nil
 }
@@ -156,6 +198,25 @@ func TestLookupDiscoveredServiceOrWorkload(t *testing.T) {
 		},
 	}
 
+	tests = append(tests, struct {
+		name          string
+		appHubType    string
+		mockClient    appHubClient
+		wantName      string
+		wantErr       bool
+		expectedError string
+	}{
+		name:       "Not Found without fallback locations",
+		appHubType: "discoveredService",
+		mockClient: &mockAppHubClient{
+			lookupDiscoveredServiceFunc: func(ctx context.Context, req *apphubpb.LookupDiscoveredServiceRequest, opts ...gax.CallOption) (*apphubpb.LookupDiscoveredServiceResponse, error) {
+				return nil, status.Error(codes.NotFound, "not found")
+			},
+		},
+		wantErr:       true,
+		expectedError: "NotFound",
+	})
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			name, err := lookupDiscoveredServiceOrWorkload(tt.mockClient, "test-project", "test-region", "test-uri", tt.appHubType, nil)
@@ -176,6 +237,80 @@ func TestLookupDiscoveredServiceOrWorkload(t *testing.T) {
 	}
 }
 
+func TestLookupDiscoveredServiceOrWorkloadFallback(t *testing.T) {
+	var parents []string
+	mockClient := &mockAppHubClient{
+		lookupDiscoveredServiceFunc: func(ctx context.Context, req *apphubpb.LookupDiscoveredServiceRequest, opts ...gax.CallOption) (*apphubpb.LookupDiscoveredServiceResponse, error) {
+			parents = append(parents, req.GetParent())
+			if !strings.HasSuffix(req.GetParent(), "/global") {
+				return nil, status.Error(codes.NotFound, "not found")
+			}
+			return &apphubpb.LookupDiscoveredServiceResponse{
+				DiscoveredService: &apphubpb.DiscoveredService{
+					Name: "test-gateway",
+				},
+			}, nil
+		},
+	}
+	asset := &assetpb.ResourceSearchResult{AssetType: "gateway.networking.k8s.io/Gateway"}
+
+	name, err := lookupDiscoveredServiceOrWorkload(mockClient, "test-project", "us-west1", "test-uri", "discoveredService", asset)
+	if err != nil {
+		t.Fatalf("lookupDiscoveredServiceOrWorkload() error = %v", err)
+	}
+	if name != "test-gateway" {
+		t.Errorf("lookupDiscoveredServiceOrWorkload() = %v, want test-gateway", name)
+	}
+	if len(parents) != 2 {
+		t.Errorf("lookupDiscoveredServiceOrWorkload() looked up %v, want the asset location then global", parents)
+	}
+}
+
+func TestGenerateAppsCloudLoggingGatewayFallback(t *testing.T) {
+	const gatewayURI = "//container.googleapis.com/projects/p/locations/us-west1/clusters/c/k8s/namespaces/shop/gateway.networking.k8s.io/gateways/web"
+	var parents []string
+	mockClient := &mockAppHubClient{
+		lookupDiscoveredServiceFunc: func(ctx context.Context, req *apphubpb.LookupDiscoveredServiceRequest, opts ...gax.CallOption) (*apphubpb.LookupDiscoveredServiceResponse, error) {
+			parents = append(parents, req.GetParent())
+			if !strings.HasSuffix(req.GetParent(), "/global") {
+				return nil, status.Error(codes.NotFound, "not found")
+			}
+			return &apphubpb.LookupDiscoveredServiceResponse{
+				DiscoveredService: &apphubpb.DiscoveredService{
+					Name: "projects/p/locations/global/discoveredServices/gateway",
+				},
+			}, nil
+		},
+	}
+	getAppHubClientFunc = func() (appHubClient, error) {
+		return mockClient, nil
+	}
+	filterLogsFunc = func(projectID, labelKey, labelValue string, locations []string) (map[string]logAsset, error) {
+		return map[string]logAsset{
+			gatewayURI: {Name: "web", AppHubType: "discoveredService", Location: "us-west1"},
+		}, nil
+	}
+	listAttachedProjectsFunc = func(apiclient appHubClient, managementProject string) (map[string]bool, error) {
+		return map[string]bool{"projects/p": true}, nil
+	}
+	defer func() {
+		getAppHubClientFunc = getAppHubClient
+		filterLogsFunc = filterLogs
+		listAttachedProjectsFunc = listAttachedProjects
+	}()
+
+	generated, err := GenerateAppsCloudLogging("p", "p", "app", "shop", []string{"us-west1"}, nil, true)
+	if err != nil {
+		t.Fatalf("GenerateAppsCloudLogging() error = %v", err)
+	}
+	if len(generated["shop"]) == 0 || generated["shop"][0] != "gateway" {
+		t.Errorf("GenerateAppsCloudLogging() = %v, want the gateway found in global", generated)
+	}
+	if len(parents) != 2 {
+		t.Errorf("GenerateAppsCloudLogging() looked up %v, want us-west1 then global", parents)
+	}
+}
+
 /*
 	tests := []struct {
 		name       string
@@ -211,7 +346,7 @@ func TestLookupDiscoveredServiceOrWorkload(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			app, err := getOrCreateAppHubApplication(tt.mockClient, "test-project", "test-region", "test-app", nil)
+			app, err := getOrCreateAppHubApplication(tt.mockClient, "test-project", "test-region", "test-app", nil, "")
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getOrCreateAppHubApplication() error = %v, wantErr %v", err, tt.wantErr)
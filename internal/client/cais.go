@@ -23,45 +23,11 @@ import (
 
 	asset "cloud.google.com/go/asset/apiv1"
 	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+	"github.com/googleapis/gax-go/v2"
 	"google.golang.org/api/iterator"
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
-var INCLUDED_ASSETS = []string{
-	// runtimes
-	"run.googleapis.com/Service",
-	"run.googleapis.com/Job",
-	"apps.k8s.io/Deployment",
-	"apps.k8s.io/DaemonSet",
-	"apps.k8s.io/StatefulSet",
-	"compute.googleapis.com/InstanceGroup",
-	"aiplatform.googleapis.com/ReasoningEngine",
-	// networking
-	"compute.googleapis.com/ForwardingRule",
-	"compute.googleapis.com/BackendService",
-	//"gateway.networking.k8s.io/Gateway",
-	// storage
-	"storage.googleapis.com/Bucket",
-	"pubsub.googleapis.com/Topic",
-	"pubsub.googleapis.com/Subscription",
-	// databases
-	"alloydb.googleapis.com/Instance",
-	"spanner.googleapis.com/Instance",
-	"sqladmin.googleapis.com/Instance",
-	"alloydb.googleapis.com/Instance",
-	"redis.googleapis.com/Instance",
-	// config
-	"secretmanager.googleapis.com/Secret",
-}
-
-var KUBERNETES_ASSETS = []string{
-	"apps.k8s.io/Deployment",
-	"apps.k8s.io/DaemonSet",
-	"apps.k8s.io/StatefulSet",
-	"k8s.io/Service",
-	"gateway.networking.k8s.io/Gateway",
-}
-
 var GKE_EXCLUSION_NAMESPACES = []string{
 	"kube-system",
 	"gmp-system",
@@ -73,25 +39,65 @@ var GKE_EXCLUSION_NAMESPACES = []string{
 	"kube-public",
 }
 
+// selectedAssetTypes holds the asset types selected by the user. When empty,
+// every search uses its default list of asset types.
+var selectedAssetTypes []string
+
 var MAX_PAGE int32 = 1000
 
 const K8S_APP_LABEL = "app.kubernetes.io/name"
 
-// searchAssets queries the Cloud Asset Inventory for resources within a specific project
-// and location
-func searchAssets(parent, labelKey, labelValue, tagKey, tagValue, contains string, locations []string, assetTypesData []byte) ([]*assetpb.ResourceSearchResult, error) {
+// allFields is the read mask of the searches that return the resource data,
+// such as versionedResources. fieldmaskpb.New rejects "*", which is not a
+// field of ResourceSearchResult.
+var allFields = &fieldmaskpb.FieldMask{Paths: []string{"*"}}
+
+// assetClient is the Asset client used by the searches
+type assetClient interface {
+	SearchAllResources(ctx context.Context, req *assetpb.SearchAllResourcesRequest, opts ...gax.CallOption) *asset.ResourceSearchResultIterator
+	Close() error
+}
+
+var getAssetClientFunc = getAssetClient
+
+func getAssetClient(ctx context.Context) (assetClient, error) {
+	return asset.NewClient(ctx)
+}
+
+// searchAllResources calls SearchAllResources and iterates over the results
+func searchAllResources(req *assetpb.SearchAllResourcesRequest) ([]*assetpb.ResourceSearchResult, error) {
 	ctx := context.Background()
-	var searchAssetTypes []string
-	var queryParts []string
 
-	logger := clilog.GetLogger()
 	// Initialize the Asset Service client
-	client, err := asset.NewClient(ctx)
+	client, err := getAssetClientFunc(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create asset client: %w", err)
 	}
 	defer client.Close()
 
+	var assets []*assetpb.ResourceSearchResult
+	it := client.SearchAllResources(ctx, req)
+
+	for {
+		asset, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error while iterating resources: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+	return assets, nil
+}
+
+// searchAssets queries the Cloud Asset Inventory for resources within a specific project
+// and location
+func searchAssets(parent, labelKey, labelValue, tagKey, tagValue, contains string, locations, assetTypes []string) ([]*assetpb.ResourceSearchResult, error) {
+	var queryParts []string
+
+	logger := clilog.GetLogger()
+
 	// Build the full search query.
 	if len(locations) > 1 {
 		queryParts = append(queryParts, fmt.Sprintf("location:(%s)", strings.Join(locations, " OR ")))
@@ -113,69 +119,43 @@ func searchAssets(parent, labelKey, labelValue, tagKey, tagValue, contains strin
 		} else {
 			queryParts = append(queryParts, fmt.Sprintf("(tagKeys:%s OR effectiveTagKeys:%s)", tagKey, tagKey))
 		}
-		// exclude kubernetes system namespaces
-		for _, ns := range GKE_EXCLUSION_NAMESPACES {
-			queryParts = append(queryParts, fmt.Sprintf("NOT parentFullResourceName : \"%s\"", ns))
-		}
 	} else if contains != "" {
 		queryParts = append(queryParts, fmt.Sprintf("name:%s", contains))
 	}
 
+	// exclude kubernetes system namespaces and configured exclusions
+	queryParts = append(queryParts, assetExclusions.queryClauses(false)...)
+
 	fullQuery := strings.Join(queryParts, " AND ")
 
 	logger.Info("Searching scope with query", "scope", parent, "query", fullQuery)
 
-	if len(assetTypesData) > 0 {
-		searchAssetTypes = strings.Split(string(assetTypesData), ",")
-	} else {
-		searchAssetTypes = INCLUDED_ASSETS
-	}
+	searchAssetTypes := assetExclusions.filterAssetTypes(assetTypes)
 
 	logger.Info("Searching asset types", "assets", searchAssetTypes)
 
-	readMask, _ := fieldmaskpb.New(&assetpb.ResourceSearchResult{}, "*")
-
 	// Construct the search request
 	req := &assetpb.SearchAllResourcesRequest{
 		Scope:      parent,
 		Query:      fullQuery,
 		AssetTypes: searchAssetTypes,
 		PageSize:   MAX_PAGE,
-		ReadMask:   readMask,
+		ReadMask:   allFields,
 	}
 
-	// Call SearchAllResources and iterate over the results
-	var assets []*assetpb.ResourceSearchResult
-	it := client.SearchAllResources(ctx, req)
-
-	for {
-		asset, err := it.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("error while iterating resources: %w", err)
-		}
-		assets = append(assets, asset)
+	assets, err := searchAllResources(req)
+	if err != nil {
+		return nil, err
 	}
-
-	return assets, nil
+	return assetExclusions.filter(assets), nil
 }
 
 // searchKubernetes queries the Cloud Asset Inventory for kubernetes resources within a specific project
-// and location
-func searchKubernetes(parent string, locations []string) ([]*assetpb.ResourceSearchResult, error) {
-	ctx := context.Background()
-	var searchAssetTypes []string
+// and location. With withResources, the resource data, such as the selector of a Service, is returned.
+func searchKubernetes(parent string, locations, assetTypes []string, withResources bool) ([]*assetpb.ResourceSearchResult, error) {
 	var queryParts []string
 
 	logger := clilog.GetLogger()
-	// Initialize the Asset Service client
-	client, err := asset.NewClient(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create asset client: %w", err)
-	}
-	defer client.Close()
 
 	// Build the full search query.
 	if len(locations) > 1 {
@@ -184,19 +164,14 @@ func searchKubernetes(parent string, locations []string) ([]*assetpb.ResourceSea
 		queryParts = []string{fmt.Sprintf("location:%s", locations[0])}
 	}
 
-	// exclude kubernetes system namespaces
-	var gkeExlNs []string
-	for _, ns := range GKE_EXCLUSION_NAMESPACES {
-		gkeExlNs = append(gkeExlNs, fmt.Sprintf("parentFullResourceName : \"%s\"", ns))
-	}
-
-	queryParts = append(queryParts, fmt.Sprintf("NOT (%s)", strings.Join(gkeExlNs, " OR ")))
+	// exclude kubernetes system namespaces and configured exclusions
+	queryParts = append(queryParts, assetExclusions.queryClauses(true)...)
 
 	fullQuery := strings.Join(queryParts, " ")
 
 	logger.Info("Searching scope with query", "scope", parent, "query", fullQuery)
 
-	searchAssetTypes = KUBERNETES_ASSETS
+	searchAssetTypes := assetExclusions.filterAssetTypes(assetTypes)
 
 	logger.Info("Searching asset types", "assets", searchAssetTypes)
 
@@ -208,39 +183,24 @@ func searchKubernetes(parent string, locations []string) ([]*assetpb.ResourceSea
 		PageSize:   MAX_PAGE,
 		OrderBy:    "parentFullResourceName",
 	}
-
-	// Call SearchAllResources and iterate over the results
-	var assets []*assetpb.ResourceSearchResult
-	it := client.SearchAllResources(ctx, req)
-
-	for {
-		asset, err := it.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("error while iterating resources: %w", err)
-		}
-		assets = append(assets, asset)
+	if withResources {
+		req.ReadMask = allFields
 	}
 
-	return assets, nil
+	assets, err := searchAllResources(req)
+	if err != nil {
+		return nil, err
+	}
+	return assetExclusions.filter(assets), nil
 }
 
 // searchKubernetesApps queries the Cloud Asset Inventory for kubernetes resources
-// that matches a specific label within a specific project and location
-func searchKubernetesApps(parent string, locations []string) ([]*assetpb.ResourceSearchResult, error) {
-	ctx := context.Background()
-	var searchAssetTypes []string
+// that have any of the label keys within a specific project and location. The
+// resource data is returned to match the pod templates with Service selectors.
+func searchKubernetesApps(parent string, locations, assetTypes, labelKeys []string) ([]*assetpb.ResourceSearchResult, error) {
 	var queryParts []string
 
 	logger := clilog.GetLogger()
-	// Initialize the Asset Service client
-	client, err := asset.NewClient(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create asset client: %w", err)
-	}
-	defer client.Close()
 
 	// Build the full search query.
 	if len(locations) > 1 {
@@ -249,22 +209,25 @@ func searchKubernetesApps(parent string, locations []string) ([]*assetpb.Resourc
 		queryParts = []string{fmt.Sprintf("location:%s", locations[0])}
 	}
 
-	// include kubernetes app label
-	queryParts = append(queryParts, fmt.Sprintf("labels.\"%s\":*", K8S_APP_LABEL))
-
-	// exclude kubernetes system namespaces
-	var gkeExlNs []string
-	for _, ns := range GKE_EXCLUSION_NAMESPACES {
-		gkeExlNs = append(gkeExlNs, fmt.Sprintf("parentFullResourceName : \"%s\"", ns))
+	// include kubernetes app labels
+	var labelParts []string
+	for _, labelKey := range labelKeys {
+		labelParts = append(labelParts, fmt.Sprintf("labels.\"%s\":*", labelKey))
+	}
+	if len(labelParts) > 1 {
+		queryParts = append(queryParts, fmt.Sprintf("(%s)", strings.Join(labelParts, " OR ")))
+	} else {
+		queryParts = append(queryParts, labelParts...)
 	}
 
-	queryParts = append(queryParts, fmt.Sprintf("NOT (%s)", strings.Join(gkeExlNs, " OR ")))
+	// exclude kubernetes system namespaces and configured exclusions
+	queryParts = append(queryParts, assetExclusions.queryClauses(true)...)
 
 	fullQuery := strings.Join(queryParts, " AND ")
 
 	logger.Info("Searching scope with query", "scope", parent, "query", fullQuery)
 
-	searchAssetTypes = KUBERNETES_ASSETS
+	searchAssetTypes := assetExclusions.filterAssetTypes(assetTypes)
 
 	logger.Info("Searching asset types", "assets", searchAssetTypes)
 
@@ -274,38 +237,20 @@ func searchKubernetesApps(parent string, locations []string) ([]*assetpb.Resourc
 		Query:      fullQuery,
 		AssetTypes: searchAssetTypes,
 		PageSize:   MAX_PAGE,
+		ReadMask:   allFields,
 	}
 
-	// Call SearchAllResources and iterate over the results
-	var assets []*assetpb.ResourceSearchResult
-	it := client.SearchAllResources(ctx, req)
-
-	for {
-		asset, err := it.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("error while iterating resources: %w", err)
-		}
-		assets = append(assets, asset)
+	assets, err := searchAllResources(req)
+	if err != nil {
+		return nil, err
 	}
-
-	return assets, nil
+	return assetExclusions.filter(assets), nil
 }
 
-func searchProject(parent string, projectIds, locations []string, assetTypesData []byte) ([]*assetpb.ResourceSearchResult, error) {
-	ctx := context.Background()
-	var searchAssetTypes []string
+func searchProject(parent string, projectIds, locations, assetTypes []string) ([]*assetpb.ResourceSearchResult, error) {
 	var queryParts []string
 
 	logger := clilog.GetLogger()
-	// Initialize the Asset Service client
-	client, err := asset.NewClient(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create asset client: %w", err)
-	}
-	defer client.Close()
 
 	// Build the full search query.
 	if len(locations) > 1 {
@@ -318,13 +263,8 @@ func searchProject(parent string, projectIds, locations []string, assetTypesData
 		queryParts = []string{fmt.Sprintf("location:%s", locations[0])}
 	}
 
-	// exclude kubernetes system namespaces
-	var gkeExlNs []string
-	for _, ns := range GKE_EXCLUSION_NAMESPACES {
-		gkeExlNs = append(gkeExlNs, fmt.Sprintf("parentFullResourceName : \"%s\"", ns))
-	}
-
-	queryParts = append(queryParts, fmt.Sprintf("NOT (%s)", strings.Join(gkeExlNs, " OR ")))
+	// exclude kubernetes system namespaces and configured exclusions
+	queryParts = append(queryParts, assetExclusions.queryClauses(false)...)
 
 	if len(projectIds) > 1 {
 		var p []string
@@ -338,55 +278,88 @@ func searchProject(parent string, projectIds, locations []string, assetTypesData
 
 	logger.Info("Searching scope with query", "scope", parent, "query", fullQuery)
 
-	if len(assetTypesData) > 0 {
-		searchAssetTypes = strings.Split(string(assetTypesData), ",")
-	} else {
-		searchAssetTypes = INCLUDED_ASSETS
-	}
+	searchAssetTypes := assetExclusions.filterAssetTypes(assetTypes)
 
 	logger.Info("Searching asset types", "assets", searchAssetTypes)
 
-	readMask, _ := fieldmaskpb.New(&assetpb.ResourceSearchResult{}, "*")
-
 	// Construct the search request
 	req := &assetpb.SearchAllResourcesRequest{
 		Scope:      parent,
 		Query:      fullQuery,
 		AssetTypes: searchAssetTypes,
 		PageSize:   MAX_PAGE,
-		ReadMask:   readMask,
+		ReadMask:   allFields,
 	}
 
-	// Call SearchAllResources and iterate over the results
-	var assets []*assetpb.ResourceSearchResult
-	it := client.SearchAllResources(ctx, req)
+	assets, err := searchAllResources(req)
+	if err != nil {
+		return nil, err
+	}
+	return assetExclusions.filter(assets), nil
+}
 
-	for {
-		asset, err := it.Next()
-		if err == iterator.Done {
-			break
+// SetAssetTypes selects the asset types searched by every generate mode. Asset
+// types are read from a comma or newline separated file and from flags. Asset
+// types that App Hub cannot register are reported and ignored.
+func SetAssetTypes(assetTypesData []byte, assetTypes []string) error {
+	logger := clilog.GetLogger()
+
+	requested := parseAssetTypes(string(assetTypesData))
+	for _, a := range assetTypes {
+		requested = append(requested, parseAssetTypes(a)...)
+	}
+
+	selectedAssetTypes = nil
+	if len(requested) == 0 {
+		return nil
+	}
+
+	supported := getSupportedAssetTypes()
+	for _, assetType := range requested {
+		if !slices.Contains(supported, assetType) {
+			logger.Warn("Asset type is not supported by App Hub and will not be searched", "assetType", assetType)
+			continue
 		}
-		if err != nil {
-			return nil, fmt.Errorf("error while iterating resources: %w", err)
+		if !slices.Contains(selectedAssetTypes, assetType) {
+			selectedAssetTypes = append(selectedAssetTypes, assetType)
 		}
-		assets = append(assets, asset)
 	}
 
-	return assets, nil
+	if len(selectedAssetTypes) == 0 {
+		return fmt.Errorf("none of the asset types are supported, supported asset types are: %s",
+			strings.Join(supported, ", "))
+	}
+	return nil
 }
 
-func identifyServiceOrWorkload(assetType string) string {
-	WORKLOADS := []string{
-		"apps.k8s.io/Deployment",
-		"apps.k8s.io/DaemonSet",
-		"apps.k8s.io/StatefulSet",
-		"run.googleapis.com/Job",
-		"compute.googleapis.com/InstanceGroup",
-		"aiplatform.googleapis.com/ReasoningEngine",
+// parseAssetTypes splits a comma or newline separated list of asset types
+func parseAssetTypes(data string) []string {
+	var assetTypes []string
+	for _, a := range strings.FieldsFunc(data, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	}) {
+		if a = strings.TrimSpace(a); a != "" {
+			assetTypes = append(assetTypes, a)
+		}
 	}
-	if slices.Contains(WORKLOADS, assetType) {
-		return "discoveredWorkload"
-	} else {
-		return "discoveredService"
+	return assetTypes
+}
+
+// getSearchAssetTypes returns the asset types a search should use. The user
+// selection, if any, replaces the defaults but is limited to the allowed
+// asset types of the generate mode.
+func getSearchAssetTypes(defaults, allowed []string) []string {
+	logger := clilog.GetLogger()
+	if len(selectedAssetTypes) == 0 {
+		return defaults
+	}
+	var assetTypes []string
+	for _, assetType := range selectedAssetTypes {
+		if len(allowed) > 0 && !slices.Contains(allowed, assetType) {
+			logger.Warn("Asset type is not applicable to this generate mode and will not be searched", "assetType", assetType)
+			continue
+		}
+		assetTypes = append(assetTypes, assetType)
 	}
+	return assetTypes
 }
@@ -15,9 +15,55 @@
 package client
 
 import (
+	"context"
+	"net"
+	"slices"
 	"testing"
+
+	asset "cloud.google.com/go/asset/apiv1"
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
 )
 
+// mockAssetServer records the search requests and returns the results. The
+// Asset client iterator cannot be built outside of its package, so the
+// searches are tested with a client connected to this server.
+type mockAssetServer struct {
+	assetpb.UnimplementedAssetServiceServer
+	requests []*assetpb.SearchAllResourcesRequest
+	results  []*assetpb.ResourceSearchResult
+}
+
+func (m *mockAssetServer) SearchAllResources(ctx context.Context, req *assetpb.SearchAllResourcesRequest) (*assetpb.SearchAllResourcesResponse, error) {
+	m.requests = append(m.requests, req)
+	return &assetpb.SearchAllResourcesResponse{Results: m.results}, nil
+}
+
+// useMockAssetServer serves the Asset API from the mock for the duration of the test
+func useMockAssetServer(t *testing.T, m *mockAssetServer) {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	assetpb.RegisterAssetServiceServer(server, m)
+	go func() { _ = server.Serve(listener) }()
+
+	getAssetClientFunc = func(ctx context.Context) (assetClient, error) {
+		return asset.NewClient(ctx,
+			option.WithEndpoint("passthrough:///bufnet"),
+			option.WithoutAuthentication(),
+			option.WithGRPCDialOption(grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			})),
+			option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())))
+	}
+	t.Cleanup(func() {
+		getAssetClientFunc = getAssetClient
+		server.Stop()
+	})
+}
+
 func TestIdentifyServiceOrWorkload(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -34,6 +80,31 @@ func TestIdentifyServiceOrWorkload(t *testing.T) {
 			assetType: "run.googleapis.com/Service",
 			want:      "discoveredService",
 		},
+		{
+			name:      "Vertex AI Agent Engine should be a workload",
+			assetType: "aiplatform.googleapis.com/ReasoningEngine",
+			want:      "discoveredWorkload",
+		},
+		{
+			name:      "Cloud Run function should be a service",
+			assetType: "cloudfunctions.googleapis.com/Function",
+			want:      "discoveredService",
+		},
+		{
+			name:      "App Engine service should be a service",
+			assetType: "appengine.googleapis.com/Service",
+			want:      "discoveredService",
+		},
+		{
+			name:      "Memorystore for Valkey should be a service",
+			assetType: "memorystore.googleapis.com/Instance",
+			want:      "discoveredService",
+		},
+		{
+			name:      "Filestore should be a service",
+			assetType: "file.googleapis.com/Instance",
+			want:      "discoveredService",
+		},
 		{
 			name:      "Unknown type should be a service",
 			assetType: "some.other.asset/Type",
@@ -49,3 +120,105 @@ func TestIdentifyServiceOrWorkload(t *testing.T) {
 		})
 	}
 }
+
+func TestParseAssetTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{
+			name: "Comma separated",
+			data: "run.googleapis.com/Service, storage.googleapis.com/Bucket",
+			want: []string{"run.googleapis.com/Service", "storage.googleapis.com/Bucket"},
+		},
+		{
+			name: "Newline separated with trailing newline",
+			data: "run.googleapis.com/Service\r\nstorage.googleapis.com/Bucket\n",
+			want: []string{"run.googleapis.com/Service", "storage.googleapis.com/Bucket"},
+		},
+		{
+			name: "Empty",
+			data: " \n",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseAssetTypes(tt.data); !slices.Equal(got, tt.want) {
+				t.Errorf("parseAssetTypes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetAssetTypes(t *testing.T) {
+	defer func() { selectedAssetTypes = nil }()
+
+	if err := SetAssetTypes([]byte("foo.googleapis.com/Bar"), nil); err == nil {
+		t.Errorf("SetAssetTypes() expected error when no asset type is supported")
+	}
+
+	if err := SetAssetTypes([]byte("run.googleapis.com/Service\nfoo.googleapis.com/Bar"),
+		[]string{"apps.k8s.io/Deployment,run.googleapis.com/Service"}); err != nil {
+		t.Fatalf("SetAssetTypes() error = %v", err)
+	}
+
+	want := []string{"run.googleapis.com/Service", "apps.k8s.io/Deployment"}
+	if got := getSearchAssetTypes(getIncludedAssetTypes(), nil); !slices.Equal(got, want) {
+		t.Errorf("getSearchAssetTypes() = %v, want %v", got, want)
+	}
+
+	want = []string{"apps.k8s.io/Deployment"}
+	if got := getSearchAssetTypes(getKubernetesAssetTypes(), getKubernetesAssetTypes()); !slices.Equal(got, want) {
+		t.Errorf("getSearchAssetTypes() = %v, want %v", got, want)
+	}
+
+	if err := SetAssetTypes(nil, nil); err != nil {
+		t.Fatalf("SetAssetTypes() error = %v", err)
+	}
+	if got := getSearchAssetTypes(getKubernetesAssetTypes(), getKubernetesAssetTypes()); !slices.Equal(got, getKubernetesAssetTypes()) {
+		t.Errorf("getSearchAssetTypes() = %v, want %v", got, getKubernetesAssetTypes())
+	}
+}
+
+func TestSearchKubernetesAppsReadMask(t *testing.T) {
+	m := &mockAssetServer{
+		results: []*assetpb.ResourceSearchResult{{
+			Name:               "//container.googleapis.com/projects/p/locations/us-west1/clusters/c/k8s/namespaces/shop/apps/deployments/cart",
+			AssetType:          "apps.k8s.io/Deployment",
+			VersionedResources: []*assetpb.VersionedResource{{Version: "v1"}},
+		}},
+	}
+	useMockAssetServer(t, m)
+
+	for _, search := range []struct {
+		name string
+		run  func() ([]*assetpb.ResourceSearchResult, error)
+	}{
+		{"searchKubernetesApps", func() ([]*assetpb.ResourceSearchResult, error) {
+			return searchKubernetesApps("projects/p", []string{"us-west1"}, []string{"apps.k8s.io/Deployment"}, []string{K8S_APP_LABEL})
+		}},
+		{"searchKubernetes", func() ([]*assetpb.ResourceSearchResult, error) {
+			return searchKubernetes("projects/p", []string{"us-west1"}, []string{"apps.k8s.io/Deployment"}, true)
+		}},
+	} {
+		t.Run(search.name, func(t *testing.T) {
+			m.requests = nil
+			got, err := search.run()
+			if err != nil {
+				t.Fatalf("%s() error = %v", search.name, err)
+			}
+			if len(m.requests) != 1 {
+				t.Fatalf("%s() sent %d requests, want 1", search.name, len(m.requests))
+			}
+			if paths := m.requests[0].GetReadMask().GetPaths(); !slices.Equal(paths, []string{"*"}) {
+				t.Errorf("%s() read mask = %v, want [*]", search.name, paths)
+			}
+			if len(got) != 1 || len(got[0].GetVersionedResources()) != 1 {
+				t.Errorf("%s() = %v, want the result with its versioned resources", search.name, got)
+			}
+		})
+	}
+}
@@ -21,21 +21,22 @@ import (
 	"fmt"
 	"internal/clilog"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 
-	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
 	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
 	resourcemanager "cloud.google.com/go/resourcemanager/apiv3"
 	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
-	"google.golang.org/api/iterator"
 )
 
 var (
 	searchAssetsFunc    = searchAssets
 	getAppHubClientFunc = getAppHubClient
+	filterLogsFunc      = filterLogs
 )
 
-var multiRegions = []string{"us", "eu", "global", "eur4", "nam3", "nam4", "nam6", "nam7", "nam8", "asia", "asia1"}
+var multiRegions = []string{"us", "eu", "global", "eur3", "eur4", "nam3", "nam4", "nam5", "nam6", "nam7", "nam8", "asia", "asia1"}
 
 // regions contains a list of region names extracted from the provided table.
 var regions = []string{
@@ -84,14 +85,16 @@ var regions = []string{
 }
 
 func GenerateAppsAssetInventory(parent, managementProject, labelKey, labelValue, tagKey, tagValue,
-	contains string, locations []string, attributesData, assetTypesData []byte, reportOnly bool,
+	contains string, locations []string, attributesData []byte, reportOnly bool,
 ) (map[string][]string, error) {
 	logger := clilog.GetLogger()
 	var appLocation string
 	generatedApplications := make(map[string][]string)
 
+	assetTypes := getSearchAssetTypes(getIncludedAssetTypes(), nil)
+
 	logger.Info("Running CAIS Search with location and Filters")
-	assets, err := searchAssetsFunc(parent, labelKey, labelValue, tagKey, tagValue, contains, locations, assetTypesData)
+	assets, err := searchAssetsFunc(parent, labelKey, labelValue, tagKey, tagValue, contains, locations, assetTypes)
 	if err != nil {
 		return generatedApplications, fmt.Errorf("error searching assets: %w", err)
 	}
@@ -120,7 +123,10 @@ func GenerateAppsAssetInventory(parent, managementProject, labelKey, labelValue,
 		return getAppName(labelKey, tagKey, contains, labelValue, tagValue, asset)
 	}
 
-	return processAssets(assets, apphubClient, managementProject, appLocation, attributesData, reportOnly, appNameFunc)
+	unattached := checkServiceProjects(apphubClient, managementProject, assets, attachServiceProjects && !reportOnly)
+
+	return processAssets(assets, apphubClient, managementProject, appLocation, attributesData, reportOnly, "", unattached,
+		appNameFunc)
 }
 
 func GenerateAppsCloudLogging(projectID, managementProject, logLabelKey, logLabelValue string,
@@ -132,7 +138,7 @@ func GenerateAppsCloudLogging(projectID, managementProject, logLabelKey, logLabe
 
 	logger.Info("Running Cloud Logging with location and Filters")
 
-	assets, err := filterLogs(projectID, logLabelKey, logLabelValue, locations)
+	assets, err := filterLogsFunc(projectID, logLabelKey, logLabelValue, locations)
 	if err != nil {
 		return generatedApplications, fmt.Errorf("error searching logs: %w", err)
 	}
@@ -157,6 +163,16 @@ func GenerateAppsCloudLogging(projectID, managementProject, logLabelKey, logLabe
 		appLocation = locations[0]
 	}
 
+	// Resources of projects that are not attached cannot be looked up
+	var projectAssets []*assetpb.ResourceSearchResult
+	for assetURI := range assets {
+		projectAssets = append(projectAssets, &assetpb.ResourceSearchResult{
+			Name:    assetURI,
+			Project: "projects/" + getURIProject(assetURI),
+		})
+	}
+	unattached := checkServiceProjects(apphubClient, managementProject, projectAssets, attachServiceProjects && !reportOnly)
+
 	// For each asset returned
 	for assetURI, asset := range assets {
 		logger.Info("Processing asset from logs", "assetURI", assetURI, "assetName", asset.Name)
@@ -168,7 +184,12 @@ func GenerateAppsCloudLogging(projectID, managementProject, logLabelKey, logLabe
 			asset.Location,
 			assetURI,
 			asset.AppHubType, nil); err != nil {
-			logger.Warn("Discovered Service/Workload not found, perhaps already registered", "assetURI", assetURI, "error", err)
+			if unattached["projects/"+getURIProject(assetURI)] {
+				logger.Warn("Discovered Service/Workload not found, the project is not attached to the management project",
+					"assetURI", assetURI, "project", getURIProject(assetURI))
+			} else {
+				logger.Warn("Discovered Service/Workload not found in App Hub", "assetURI", assetURI, "error", err)
+			}
 		}
 
 		// If the discovered name is not empty,
@@ -184,8 +205,17 @@ func GenerateAppsCloudLogging(projectID, managementProject, logLabelKey, logLabe
 
 			// perform the action is reportOnly is false
 			if !reportOnly {
+				if slices.Contains(GetUnmanagedApplications(), appName) {
+					continue
+				}
 				// create the application if it does not exist
-				if _, err = getOrCreateAppHubApplication(apphubClient, managementProject, appLocation, appName, attributesData); err != nil {
+				if _, err = getOrCreateAppHubApplication(apphubClient, managementProject, appLocation, appName, attributesData,
+					getProvenanceMarker("")); isUnmanaged(err) {
+					logger.Warn("Skipping application, it was not created by apphub-app-creator", "application", appName,
+						"error", err)
+					skipUnmanaged(appName)
+					continue
+				} else if err != nil {
 					logger.Error("Failed to create or get application", "application", appName, "error", err)
 					return generatedApplications, fmt.Errorf("error creating application: %w", err)
 				}
@@ -210,54 +240,33 @@ func GenerateAppsCloudLogging(projectID, managementProject, logLabelKey, logLabe
 }
 
 func DeleteAllApps(managementProject string, locations []string) error {
-	logger := clilog.GetLogger()
-	ctx := context.Background()
-	apphubClient, err := getAppHubClientFunc()
+	plan, err := PlanDeletion(managementProject, "", locations, false)
 	if err != nil {
-		return fmt.Errorf("error getting apphub client: %w", err)
-	}
-
-	defer closeAppHubClient(apphubClient)
-
-	logger.Info("Attempting deletion of applications")
-	for _, location := range locations {
-		// Parent format: projects/{project}/locations/{location}/applications/{application_id}
-		parent := fmt.Sprintf("projects/%s/locations/%s", managementProject, location)
-		req := &apphubpb.ListApplicationsRequest{
-			Parent: parent,
-		}
-
-		// Call the ListApplications API
-		listApplications := apphubClient.ListApplications(ctx, req)
-		for {
-			app, err := listApplications.Next()
-			if err != nil {
-				if err == iterator.Done {
-					break
-				}
-				return fmt.Errorf("failed to list applications: %w", err)
-			}
-
-			appName := app.Name[strings.LastIndex(app.Name, "/")+1:]
-			logger.Info("Deleting application", "application", appName, "location", location)
-			if err = deleteApp(apphubClient, managementProject, location, appName); err != nil {
-				return fmt.Errorf("error deleting application %s: %w", appName, err)
-			}
-		}
+		return err
 	}
-	logger.Info("Successfully finished deleting applications.")
-	return nil
+	_, err = DeleteApps(managementProject, plan, DEFAULT_DELETION_CONCURRENCY, nil)
+	return err
 }
 
+// GenerateAppsPerNamespace creates an application per Kubernetes namespace. With the
+// cluster grouping, the namespace of every cluster is a separate application. The other
+// groupings key only on the namespace (optionally with its project or fleet), and their
+// applications are always global so a namespace keeps its application as it spreads
+// to more clusters.
 func GenerateAppsPerNamespace(parent, managementProject string, locations []string,
-	attributesData []byte, reportOnly bool,
+	attributesData []byte, groupBy string, reportOnly bool,
 ) (map[string][]string, error) {
 	logger := clilog.GetLogger()
 	var appLocation string
 	generatedApplications := make(map[string][]string)
 
+	assetTypes := getSearchAssetTypes(getKubernetesAssetTypes(), getKubernetesAssetTypes())
+	if len(assetTypes) == 0 {
+		return generatedApplications, fmt.Errorf("none of the selected asset types are Kubernetes asset types")
+	}
+
 	logger.Info("Running CAIS Search with location and Filters")
-	assets, err := searchKubernetes(parent, locations)
+	assets, err := searchKubernetes(parent, locations, assetTypes, false)
 	if err != nil {
 		return generatedApplications, fmt.Errorf("error searching assets: %w", err)
 	}
@@ -281,27 +290,89 @@ func GenerateAppsPerNamespace(parent, managementProject string, locations []stri
 	} else {
 		appLocation = locations[0]
 	}
+	appLocation = getNamespaceAppLocation(groupBy, appLocation)
 
-	appNameFunc := func(asset *assetpb.ResourceSearchResult) string {
-		return getAppNameForKubernetes(asset.ParentFullResourceName)
+	unattached := checkServiceProjects(apphubClient, managementProject, assets, attachServiceProjects && !reportOnly)
+
+	if groupBy == "" || groupBy == NAMESPACE_GROUP_CLUSTER {
+		appNameFunc := func(asset *assetpb.ResourceSearchResult) string {
+			return getAppNameForKubernetes(asset.ParentFullResourceName)
+		}
+		return processAssets(assets, apphubClient, managementProject, appLocation, attributesData, reportOnly, "", unattached,
+			appNameFunc)
+	}
+
+	groups, err := groupNamespaces(assets, newNamespaceGrouper(groupBy))
+	if err != nil {
+		return generatedApplications, fmt.Errorf("error grouping namespaces: %w", err)
 	}
 
-	return processAssets(assets, apphubClient, managementProject, appLocation, attributesData, reportOnly, appNameFunc)
+	appNames := make([]string, 0, len(groups))
+	for appName := range groups {
+		appNames = append(appNames, appName)
+	}
+	sort.Strings(appNames)
+
+	for _, appName := range appNames {
+		group := groups[appName]
+		logger.Info("Processing namespace", "application", appName, "location", appLocation, "clusters", group.clusters)
+
+		apps, err := processAssets(group.assets, apphubClient, managementProject, appLocation, attributesData, reportOnly, "",
+			unattached, func(asset *assetpb.ResourceSearchResult) string {
+				return appName
+			})
+		for name, members := range apps {
+			generatedApplications[name] = append(generatedApplications[name], members...)
+		}
+		if err != nil {
+			return generatedApplications, err
+		}
+		if reportOnly || len(apps) == 0 {
+			continue
+		}
+		applicationName := fmt.Sprintf("projects/%s/locations/%s/applications/%s", managementProject, appLocation, appName)
+		if err = describeNamespaceClusters(apphubClient, applicationName, group.clusters); err != nil {
+			return generatedApplications, fmt.Errorf("error describing application clusters: %w", err)
+		}
+	}
+	return generatedApplications, nil
 }
 
-func GenerateKubernetesApps(parent, managementProject string, locations []string, attributesData []byte,
+// GenerateKubernetesApps creates an application per value of the Kubernetes label
+// keys. The keys are evaluated in order and the first key with a valid value names
+// the application of a resource. Kubernetes Services and Gateways without the labels
+// are added to the application of the workloads in their namespace.
+func GenerateKubernetesApps(parent, managementProject string, locations, labelKeys []string, attributesData []byte,
 	reportOnly bool,
 ) (map[string][]string, error) {
 	logger := clilog.GetLogger()
 	var appLocation string
 	generatedApplications := make(map[string][]string)
 
+	assetTypes := getSearchAssetTypes(getKubernetesAssetTypes(), getKubernetesAssetTypes())
+	if len(assetTypes) == 0 {
+		return generatedApplications, fmt.Errorf("none of the selected asset types are Kubernetes asset types")
+	}
+
+	if len(labelKeys) == 0 {
+		labelKeys = []string{K8S_APP_LABEL}
+	}
+
 	logger.Info("Running CAIS Search with location and Filters")
-	assets, err := searchKubernetesApps(parent, locations)
+	labeled, err := searchKubernetesApps(parent, locations, assetTypes, labelKeys)
 	if err != nil {
 		return generatedApplications, fmt.Errorf("error searching assets: %w", err)
 	}
 
+	var services []*assetpb.ResourceSearchResult
+	if serviceTypes := getK8sServiceAssetTypes(assetTypes); len(serviceTypes) > 0 {
+		if services, err = searchKubernetes(parent, locations, serviceTypes, true); err != nil {
+			return generatedApplications, fmt.Errorf("error searching assets: %w", err)
+		}
+	}
+
+	assets, appNames := groupKubernetesApps(labeled, services, labelKeys)
+
 	if len(assets) == 0 {
 		logger.Warn("No assets found that matched the filter")
 		return generatedApplications, fmt.Errorf("no assets found that matched the filter")
@@ -323,10 +394,13 @@ func GenerateKubernetesApps(parent, managementProject string, locations []string
 	}
 
 	appNameFunc := func(asset *assetpb.ResourceSearchResult) string {
-		return asset.GetLabels()[K8S_APP_LABEL]
+		return appNames[asset.GetName()]
 	}
 
-	return processAssets(assets, apphubClient, managementProject, appLocation, attributesData, reportOnly, appNameFunc)
+	unattached := checkServiceProjects(apphubClient, managementProject, assets, attachServiceProjects && !reportOnly)
+
+	return processAssets(assets, apphubClient, managementProject, appLocation, attributesData, reportOnly, "", unattached,
+		appNameFunc)
 }
 
 func GenerateFromAll(parent, managementProject string, locations []string, attributesData []byte,
@@ -337,8 +411,11 @@ func GenerateFromAll(parent, managementProject string, locations []string, attri
 	var assets []*assetpb.ResourceSearchResult
 	generatedApplications := make(map[string][]string)
 
+	assetTypes := getSearchAssetTypes(getIncludedAssetTypes(), nil)
+	kubernetesAssetTypes := getSearchAssetTypes(getKubernetesAssetTypes(), getKubernetesAssetTypes())
+
 	logger.Info("Running CAIS Search with location and Filters")
-	labeledAssets, err := searchAssetsFunc(parent, "app*", "", "", "", "", locations, nil)
+	labeledAssets, err := searchAssetsFunc(parent, "app*", "", "", "", "", locations, assetTypes)
 	if err != nil {
 		return generatedApplications, fmt.Errorf("error searching assets: %w", err)
 	}
@@ -350,7 +427,7 @@ func GenerateFromAll(parent, managementProject string, locations []string, attri
 	}
 
 	logger.Info("Running CAIS Search with location and Filters")
-	taggedAssets, err := searchAssetsFunc(parent, "", "", "app*", "", "", locations, nil)
+	taggedAssets, err := searchAssetsFunc(parent, "", "", "app*", "", "", locations, assetTypes)
 	if err != nil {
 		return generatedApplications, fmt.Errorf("error searching assets: %w", err)
 	}
@@ -360,15 +437,17 @@ func GenerateFromAll(parent, managementProject string, locations []string, attri
 		assets = append(assets, taggedAssets...)
 	}
 
-	logger.Info("Running CAIS Search for Kubernetes labels")
-	kubernetesAssets, err := searchKubernetes(parent, locations)
-	if err != nil {
-		return generatedApplications, fmt.Errorf("error searching assets: %w", err)
-	}
+	if len(kubernetesAssetTypes) > 0 {
+		logger.Info("Running CAIS Search for Kubernetes labels")
+		kubernetesAssets, err := searchKubernetes(parent, locations, kubernetesAssetTypes, false)
+		if err != nil {
+			return generatedApplications, fmt.Errorf("error searching assets: %w", err)
+		}
 
-	logger.Info("Found assets that matched Kubernetes labels to process", "count", len(kubernetesAssets))
-	if len(kubernetesAssets) > 0 {
-		assets = append(assets, kubernetesAssets...)
+		logger.Info("Found assets that matched Kubernetes labels to process", "count", len(kubernetesAssets))
+		if len(kubernetesAssets) > 0 {
+			assets = append(assets, kubernetesAssets...)
+		}
 	}
 
 	if len(locations) > 1 {
@@ -391,11 +470,14 @@ func GenerateFromAll(parent, managementProject string, locations []string, attri
 
 	defer closeAppHubClient(apphubClient)
 
-	return processAssets(assets, apphubClient, managementProject, appLocation, attributesData, reportOnly, getAppNameFromAsset)
+	unattached := checkServiceProjects(apphubClient, managementProject, assets, attachServiceProjects && !reportOnly)
+
+	return processAssets(assets, apphubClient, managementProject, appLocation, attributesData, reportOnly, "", unattached,
+		getAppNameFromAsset)
 }
 
-func GenerateFromProject(parent, managementProject, appName string, projectIds, locations []string, attributesData,
-	assetTypesData []byte, reportOnly bool,
+func GenerateFromProject(parent, managementProject, appName string, projectIds, locations []string, attributesData []byte,
+	reportOnly bool,
 ) (map[string][]string, error) {
 	logger := clilog.GetLogger()
 	var appLocation string
@@ -404,7 +486,7 @@ func GenerateFromProject(parent, managementProject, appName string, projectIds,
 	generatedApplications := make(map[string][]string)
 
 	logger.Info("Running CAIS Search with location and Filters")
-	assets, err := searchProject(parent, projectIds, locations, assetTypesData)
+	assets, err := searchProject(parent, projectIds, locations, getSearchAssetTypes(getIncludedAssetTypes(), nil))
 	if err != nil {
 		return generatedApplications, fmt.Errorf("error searching assets: %w", err)
 	}
@@ -433,30 +515,26 @@ func GenerateFromProject(parent, managementProject, appName string, projectIds,
 		return appName
 	}
 
-	return processAssets(assets, apphubClient, managementProject, appLocation, attributesData, reportOnly, appNameFunc)
+	unattached := checkServiceProjects(apphubClient, managementProject, assets, attachServiceProjects && !reportOnly)
+
+	return processAssets(assets, apphubClient, managementProject, appLocation, attributesData, reportOnly, "", unattached,
+		appNameFunc)
 }
 
 func DeleteApp(managementProject, name string, locations []string) error {
-	logger := clilog.GetLogger()
-	apphubClient, err := getAppHubClientFunc()
+	plan, err := PlanDeletion(managementProject, name, locations, false)
 	if err != nil {
-		return fmt.Errorf("error getting apphub client: %w", err)
+		return err
 	}
-
-	defer closeAppHubClient(apphubClient)
-
-	logger.Info("Attempting deletion of application " + name)
-	for _, location := range locations {
-		if err = deleteApp(apphubClient, managementProject, location, name); err != nil {
-			return fmt.Errorf("error deleting application %s: %w", name, err)
-		}
+	if len(plan) == 0 {
+		return fmt.Errorf("application %s not found in locations %v", name, locations)
 	}
-	logger.Info("Successfully finished deleting application.")
-	return nil
+	_, err = DeleteApps(managementProject, plan, DEFAULT_DELETION_CONCURRENCY, nil)
+	return err
 }
 
 func processAssets(assets []*assetpb.ResourceSearchResult, apphubClient appHubClient, managementProject, appLocation string,
-	attributesData []byte, reportOnly bool,
+	attributesData []byte, reportOnly bool, rule string, unattached map[string]bool,
 	getAppNameFunc func(asset *assetpb.ResourceSearchResult) string,
 ) (map[string][]string, error) {
 	logger := clilog.GetLogger()
@@ -473,7 +551,7 @@ func processAssets(assets []*assetpb.ResourceSearchResult, apphubClient appHubCl
 		// Identity if it is a service or workload
 		appHubType := identifyServiceOrWorkload(asset.AssetType)
 
-		if assetRegion, err = describeRegion(asset.Location); err != nil {
+		if assetRegion, err = describeRegion(getAssetLocation(asset)); err != nil {
 			logger.Warn("Skipping asset from App Hub look up, unsupported region or zonal resource", "location", asset.Location)
 			continue
 		}
@@ -483,13 +561,22 @@ func processAssets(assets []*assetpb.ResourceSearchResult, apphubClient appHubCl
 			continue
 		}
 
-		// Lookup App Hub to get the discovered name
-		if discoveredName, err = lookupDiscoveredServiceOrWorkload(apphubClient, managementProject,
+		// Lookup App Hub to get the discovered name, unless a previous watch iteration did
+		if discoveredName = watchState.getDiscoveredName(assetRegion, asset.Name); discoveredName != "" {
+			logger.Info("Using discovered name from the previous iteration", "assetName", asset.Name)
+		} else if discoveredName, err = lookupDiscoveredServiceOrWorkload(apphubClient, managementProject,
 			assetRegion,
 			asset.Name,
 			appHubType,
 			asset); err != nil {
-			logger.Warn("Discovered Service/Workload not found, perhaps already registered", "assetName", asset.Name, "error", err)
+			if unattached[asset.GetProject()] {
+				logger.Warn("Discovered Service/Workload not found, the project is not attached to the management project",
+					"assetName", asset.Name, "project", asset.GetProject())
+			} else {
+				logger.Warn("Discovered Service/Workload not found in App Hub", "assetName", asset.Name, "error", err)
+			}
+		} else {
+			watchState.setDiscoveredName(assetRegion, asset.Name, discoveredName)
 		}
 		// If the discovered name is not empty,
 		if discoveredName != "" {
@@ -503,10 +590,25 @@ func processAssets(assets []*assetpb.ResourceSearchResult, apphubClient appHubCl
 
 			// perform the action is reportOnly is false
 			if !reportOnly {
-				// create the application if it does not exist
-				if _, err = getOrCreateAppHubApplication(apphubClient, managementProject, appLocation, appName, attributesData); err != nil {
-					logger.Error("Failed to create or get application", "application", appName, "error", err)
-					return generatedApplications, fmt.Errorf("error creating application: %w", err)
+				applicationName := fmt.Sprintf("projects/%s/locations/%s/applications/%s", managementProject, appLocation, appName)
+				if watchState.isRegistered(discoveredName, applicationName) || slices.Contains(GetUnmanagedApplications(), appName) {
+					continue
+				}
+
+				// create the application if it does not exist, other groups are processed
+				// when an existing application is not managed by the tool
+				if !watchState.hasApplication(applicationName) {
+					if _, err = getOrCreateAppHubApplication(apphubClient, managementProject, appLocation, appName, attributesData,
+						getProvenanceMarker(rule)); isUnmanaged(err) {
+						logger.Warn("Skipping application, it was not created by apphub-app-creator", "application", appName,
+							"error", err)
+						skipUnmanaged(appName)
+						continue
+					} else if err != nil {
+						logger.Error("Failed to create or get application", "application", appName, "error", err)
+						return generatedApplications, fmt.Errorf("error creating application: %w", err)
+					}
+					watchState.addApplication(applicationName)
 				}
 				displayName := asset.Name[strings.LastIndex(asset.Name, "/")+1:]
 
@@ -516,6 +618,7 @@ func processAssets(assets []*assetpb.ResourceSearchResult, apphubClient appHubCl
 					logger.Error("Failed to register service with application", "application", appName, "service", displayName, "error", err)
 					return generatedApplications, fmt.Errorf("error registering service: %w", err)
 				}
+				watchState.setRegistered(discoveredName, applicationName)
 			}
 		}
 	}
@@ -0,0 +1,160 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CoverageCount is the number of discovered services and workloads that are
+// registered in an application versus not
+type CoverageCount struct {
+	Registered   int     `json:"registered"`
+	Unregistered int     `json:"unregistered"`
+	Total        int     `json:"total"`
+	Coverage     float64 `json:"coverage"`
+}
+
+// CoverageReport summarizes App Hub onboarding per project, location and
+// resource type
+type CoverageReport struct {
+	Timestamp         string                    `json:"timestamp"`
+	ManagementProject string                    `json:"managementProject"`
+	Overall           *CoverageCount            `json:"overall"`
+	Projects          map[string]*CoverageCount `json:"projects"`
+	Locations         map[string]*CoverageCount `json:"locations"`
+	ResourceTypes     map[string]*CoverageCount `json:"resourceTypes"`
+}
+
+// GetCoverage computes how many of the discovered services and workloads in
+// the management project are registered in an application. The total is the
+// registered resources of the locations and the discovered resources that are
+// not registered.
+func GetCoverage(managementProject string, locations []string) (*CoverageReport, error) {
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	registered, err := listRegistered(apphubClient, managementProject, locations)
+	if err != nil {
+		return nil, err
+	}
+
+	report := newCoverageReport(managementProject)
+	for _, resource := range registered {
+		// global applications may hold resources of locations outside the report
+		if slices.Contains(locations, getNameLocation(resource.Name)) {
+			report.add(resource, true)
+		}
+	}
+	for _, location := range locations {
+		discovered, err := listDiscoveredFunc(apphubClient, managementProject, location)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range discovered {
+			if _, ok := registered[d.Name]; !ok {
+				report.add(d, false)
+			}
+		}
+	}
+	report.compute()
+	return report, nil
+}
+
+func newCoverageReport(managementProject string) *CoverageReport {
+	return &CoverageReport{
+		Timestamp:         time.Now().UTC().Format(time.RFC3339),
+		ManagementProject: managementProject,
+		Overall:           &CoverageCount{},
+		Projects:          make(map[string]*CoverageCount),
+		Locations:         make(map[string]*CoverageCount),
+		ResourceTypes:     make(map[string]*CoverageCount),
+	}
+}
+
+// add counts a resource in every dimension of the report
+func (r *CoverageReport) add(resource UnregisteredResource, registered bool) {
+	counts := []*CoverageCount{
+		r.Overall,
+		getCoverageCount(r.Projects, strings.TrimPrefix(resource.Project, "projects/")),
+		getCoverageCount(r.Locations, resource.Location),
+		getCoverageCount(r.ResourceTypes, getURIResourceType(resource.URI)),
+	}
+	for _, c := range counts {
+		c.Total++
+		if registered {
+			c.Registered++
+		} else {
+			c.Unregistered++
+		}
+	}
+}
+
+// compute sets the coverage percentage of every count in the report
+func (r *CoverageReport) compute() {
+	r.Overall.compute()
+	for _, m := range []map[string]*CoverageCount{r.Projects, r.Locations, r.ResourceTypes} {
+		for _, c := range m {
+			c.compute()
+		}
+	}
+}
+
+// compute sets the coverage percentage. Nothing to register is full coverage.
+func (c *CoverageCount) compute() {
+	if c.Total == 0 {
+		c.Coverage = 100
+		return
+	}
+	c.Coverage = float64(c.Registered) * 100 / float64(c.Total)
+}
+
+func getCoverageCount(m map[string]*CoverageCount, key string) *CoverageCount {
+	if key == "" {
+		key = "unknown"
+	}
+	if _, ok := m[key]; !ok {
+		m[key] = &CoverageCount{}
+	}
+	return m[key]
+}
+
+// getNameLocation returns the location of an App Hub resource name like
+// projects/p/locations/l/discoveredServices/id
+func getNameLocation(name string) string {
+	parts := strings.Split(name, "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "locations" {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// getURIResourceType returns the service and collection of a resource URI,
+// for example run.googleapis.com/services
+func getURIResourceType(uri string) string {
+	parts := strings.Split(strings.TrimPrefix(uri, "//"), "/")
+	if len(parts) < 3 {
+		return parts[0]
+	}
+	return parts[0] + "/" + parts[len(parts)-2]
+}
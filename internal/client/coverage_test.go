@@ -0,0 +1,122 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"testing"
+)
+
+func TestCoverageReport(t *testing.T) {
+	discovered := []UnregisteredResource{
+		{
+			Name:     "ds/1",
+			URI:      "//run.googleapis.com/projects/p1/locations/us-west1/services/checkout",
+			Project:  "projects/p1",
+			Location: "us-west1",
+		},
+		{
+			Name:     "ds/2",
+			URI:      "//run.googleapis.com/projects/p1/locations/us-west1/services/cart",
+			Project:  "projects/p1",
+			Location: "us-west1",
+		},
+		{
+			Name:     "ds/3",
+			URI:      "//storage.googleapis.com/bucket",
+			Project:  "projects/p2",
+			Location: "us",
+		},
+		{
+			Name:     "dw/1",
+			URI:      "//container.googleapis.com/projects/p2/locations/us-west1/clusters/c/k8s/namespaces/shop/apps/deployments/web",
+			Project:  "projects/p2",
+			Location: "us-west1",
+		},
+	}
+	registered := map[string]bool{"ds/1": true, "dw/1": true}
+
+	report := newCoverageReport("host")
+	for _, d := range discovered {
+		report.add(d, registered[d.Name])
+	}
+	report.compute()
+
+	if report.Overall.Total != 4 || report.Overall.Registered != 2 || report.Overall.Coverage != 50 {
+		t.Errorf("Overall = %+v", report.Overall)
+	}
+	if c := report.Projects["p1"]; c == nil || c.Registered != 1 || c.Unregistered != 1 {
+		t.Errorf("Projects[p1] = %+v", c)
+	}
+	if c := report.Locations["us-west1"]; c == nil || c.Total != 3 || c.Registered != 2 {
+		t.Errorf("Locations[us-west1] = %+v", c)
+	}
+	if c := report.ResourceTypes["run.googleapis.com/services"]; c == nil || c.Total != 2 || c.Coverage != 50 {
+		t.Errorf("ResourceTypes[run.googleapis.com/services] = %+v", c)
+	}
+	if c := report.ResourceTypes["container.googleapis.com/deployments"]; c == nil || c.Coverage != 100 {
+		t.Errorf("ResourceTypes[container.googleapis.com/deployments] = %+v", c)
+	}
+	if c := report.ResourceTypes["storage.googleapis.com"]; c == nil || c.Coverage != 0 {
+		t.Errorf("ResourceTypes[storage.googleapis.com] = %+v", c)
+	}
+}
+
+func TestCoverageCountEmpty(t *testing.T) {
+	c := &CoverageCount{}
+	c.compute()
+	if c.Coverage != 100 {
+		t.Errorf("compute() = %v, want 100 when there is nothing to register", c.Coverage)
+	}
+}
+
+func TestGetCoverageInGlobalApplication(t *testing.T) {
+	const (
+		checkout = "projects/host/locations/us-west1/discoveredServices/checkout"
+		cart     = "projects/host/locations/us-west1/discoveredServices/cart"
+		billing  = "projects/host/locations/us-east1/discoveredServices/billing"
+	)
+	listRegisteredFunc = func(apiclient appHubClient, projectID, location string) (map[string]UnregisteredResource, error) {
+		if location == "global" {
+			return map[string]UnregisteredResource{
+				checkout: {Name: checkout, Project: "projects/p1", Location: "us-west1"},
+				billing:  {Name: billing, Project: "projects/p1", Location: "us-east1"},
+			}, nil
+		}
+		return map[string]UnregisteredResource{}, nil
+	}
+	// App Hub lists only the discovered resources that are not registered
+	listDiscoveredFunc = func(apiclient appHubClient, projectID, location string) ([]UnregisteredResource, error) {
+		return []UnregisteredResource{{Name: cart, Project: "projects/p1", Location: location}}, nil
+	}
+	getAppHubClientFunc = func() (appHubClient, error) {
+		return &mockAppHubClient{}, nil
+	}
+	defer func() {
+		listRegisteredFunc = listRegisteredResources
+		listDiscoveredFunc = listDiscovered
+		getAppHubClientFunc = getAppHubClient
+	}()
+
+	report, err := GetCoverage("host", []string{"us-west1"})
+	if err != nil {
+		t.Fatalf("GetCoverage() error = %v", err)
+	}
+	if report.Overall.Total != 2 || report.Overall.Registered != 1 || report.Overall.Coverage != 50 {
+		t.Errorf("Overall = %+v, want the registered checkout and the unregistered cart", report.Overall)
+	}
+	if c := report.Projects["p1"]; c == nil || c.Registered != 1 || c.Unregistered != 1 {
+		t.Errorf("Projects[p1] = %+v", c)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"internal/clilog"
+	"sync"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PlanDeletion returns the applications, with their services and workloads,
+// that a delete would remove. When name is empty, every application in the
+// locations is returned. Applications the tool did not create are skipped
+// unless includeUnmanaged is set.
+func PlanDeletion(managementProject, name string, locations []string, includeUnmanaged bool) ([]ApplicationDescription, error) {
+	ctx := context.Background()
+	logger := clilog.GetLogger()
+
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	var apps []*apphubpb.Application
+	for _, location := range locations {
+		parent := fmt.Sprintf("projects/%s/locations/%s", managementProject, location)
+
+		if name != "" {
+			app, err := apphubClient.GetApplication(ctx, &apphubpb.GetApplicationRequest{
+				Name: fmt.Sprintf("%s/applications/%s", parent, name),
+			})
+			if err != nil {
+				if st, ok := status.FromError(err); ok && st.Code() == codes.NotFound {
+					logger.Info("Application not found", "name", name, "location", location)
+					continue
+				}
+				return nil, fmt.Errorf("failed to get application %s: %w", name, err)
+			}
+			if !isManagedApp(app) && !includeUnmanaged {
+				return nil, errUnmanaged(app.GetName())
+			}
+			apps = append(apps, app)
+			continue
+		}
+
+		listApplications := apphubClient.ListApplications(ctx, &apphubpb.ListApplicationsRequest{Parent: parent})
+		for {
+			app, err := listApplications.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to list applications: %w", err)
+			}
+			if !isManagedApp(app) && !includeUnmanaged {
+				logger.Warn("Skipping application not created by apphub-app-creator", "application", app.GetName())
+				continue
+			}
+			apps = append(apps, app)
+		}
+	}
+
+	var plan []ApplicationDescription
+	for _, app := range apps {
+		description := ApplicationDescription{
+			ApplicationSummary: newApplicationSummary(app),
+			Description:        app.GetDescription(),
+		}
+		if description.Members, err = listMembers(apphubClient, app.GetName()); err != nil {
+			return nil, err
+		}
+		description.countMembers()
+		plan = append(plan, description)
+	}
+	return plan, nil
+}
+
+// DEFAULT_DELETION_CONCURRENCY is the default number of delete operations in flight
+const DEFAULT_DELETION_CONCURRENCY = 4
+
+// deletionBudget limits the number of delete operations in flight, shared
+// across every application and location being deleted
+type deletionBudget chan struct{}
+
+func newDeletionBudget(concurrency int) deletionBudget {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return make(deletionBudget, concurrency)
+}
+
+func (b deletionBudget) acquire() {
+	b <- struct{}{}
+}
+
+func (b deletionBudget) release() {
+	<-b
+}
+
+// DeletionResult is the outcome of deleting an application
+type DeletionResult struct {
+	ID       string `json:"id"`
+	Location string `json:"location"`
+	Error    error  `json:"-"`
+}
+
+// DeleteApps deletes the applications of a deletion plan along with their
+// services and workloads. Applications are deleted concurrently, with at most
+// concurrency delete operations in flight across every application. A failed
+// application does not stop the others; progress, when set, is called as each
+// application finishes. The result of every application is returned, and an
+// error if any of them could not be deleted.
+func DeleteApps(managementProject string, apps []ApplicationDescription, concurrency int,
+	progress func(result DeletionResult, done, total int),
+) ([]DeletionResult, error) {
+	logger := clilog.GetLogger()
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	budget := newDeletionBudget(concurrency)
+	results := make([]DeletionResult, len(apps))
+
+	var mu sync.Mutex
+	var done, failed int
+
+	// Limit the applications being listed and deleted at once, the delete
+	// operations themselves are limited by the budget
+	var g errgroup.Group
+	g.SetLimit(cap(budget))
+
+	logger.Info("Starting application deletion...", "applications", len(apps), "maxConcurrency", cap(budget))
+
+	for i, app := range apps {
+		iCopy, appCopy := i, app
+
+		g.Go(func() error {
+			logger.Info("Deleting application", "application", appCopy.ID, "location", appCopy.Location)
+			result := DeletionResult{ID: appCopy.ID, Location: appCopy.Location}
+			if result.Error = deleteApp(apphubClient, budget, managementProject, appCopy.Location, appCopy.ID); result.Error != nil {
+				logger.Error("Failed to delete application", "application", appCopy.ID, "error", result.Error)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[iCopy] = result
+			done++
+			if result.Error != nil {
+				failed++
+			}
+			if progress != nil {
+				progress(result, done, len(apps))
+			}
+			// continue with the other applications on error
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	if failed > 0 {
+		return results, fmt.Errorf("failed to delete %d of %d applications", failed, len(apps))
+	}
+	logger.Info("Successfully finished deleting applications.")
+	return results, nil
+}
+
+// countMembers sets the number of services and workloads from the members
+func (d *ApplicationDescription) countMembers() {
+	d.Services, d.Workloads = 0, 0
+	for _, m := range d.Members {
+		if m.AppHubType == "discoveredService" {
+			d.Services++
+		} else {
+			d.Workloads++
+		}
+	}
+}
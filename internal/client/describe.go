@@ -0,0 +1,204 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"internal/clilog"
+	"slices"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ApplicationMember is a service or workload registered in an application
+type ApplicationMember struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	DisplayName    string `json:"displayName,omitempty"`
+	AppHubType     string `json:"appHubType"`
+	DiscoveredName string `json:"discoveredName"`
+	URI            string `json:"uri"`
+	Project        string `json:"project"`
+	Location       string `json:"location"`
+	AttributeSummary
+	// Labels and Tags of the underlying resource, read from CAIS
+	Labels map[string]string `json:"labels,omitempty"`
+	Tags   map[string]string `json:"tags,omitempty"`
+
+	// registration is the Service or Workload the member was read from
+	registration proto.Message
+}
+
+// ApplicationDescription is an application with every registered service and workload
+type ApplicationDescription struct {
+	ApplicationSummary
+	Description string              `json:"description,omitempty"`
+	Members     []ApplicationMember `json:"members"`
+}
+
+// DescribeApp returns an application and its services and workloads. The
+// application is looked up in each location in order. When enrich is set, the
+// labels and tags of the underlying resources are read from CAIS.
+func DescribeApp(managementProject, name string, locations []string, enrich bool) (*ApplicationDescription, error) {
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	app, err := findApplication(apphubClient, managementProject, name, locations)
+	if err != nil {
+		return nil, err
+	}
+
+	description := &ApplicationDescription{
+		ApplicationSummary: newApplicationSummary(app),
+		Description:        app.GetDescription(),
+	}
+	if description.Members, err = listMembers(apphubClient, app.GetName()); err != nil {
+		return nil, err
+	}
+	description.countMembers()
+
+	if enrich {
+		if err = enrichMembers(description.Members); err != nil {
+			return nil, err
+		}
+	}
+	return description, nil
+}
+
+// NotFoundError is returned when an application does not exist in any of the locations
+type NotFoundError struct {
+	Name      string
+	Locations []string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("application %s not found in locations %v", e.Name, e.Locations)
+}
+
+// findApplication looks up an application in each location in order and
+// returns the first one found
+func findApplication(apiclient appHubClient, managementProject, name string, locations []string) (*apphubpb.Application, error) {
+	ctx := context.Background()
+	logger := clilog.GetLogger()
+
+	for _, location := range locations {
+		appName := fmt.Sprintf("projects/%s/locations/%s/applications/%s", managementProject, location, name)
+		app, err := apiclient.GetApplication(ctx, &apphubpb.GetApplicationRequest{Name: appName})
+		if err == nil {
+			return app, nil
+		}
+		if st, ok := status.FromError(err); ok && st.Code() == codes.NotFound {
+			logger.Info("Application not found", "name", name, "location", location)
+			continue
+		}
+		return nil, fmt.Errorf("failed to get application %s: %w", name, err)
+	}
+	return nil, &NotFoundError{Name: name, Locations: locations}
+}
+
+// listMembers returns the services and workloads registered in an application
+func listMembers(apiclient appHubClient, appName string) ([]ApplicationMember, error) {
+	ctx := context.Background()
+	var members []ApplicationMember
+
+	listServices := apiclient.ListServices(ctx, &apphubpb.ListServicesRequest{Parent: appName})
+	for {
+		service, err := listServices.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list services: %w", err)
+		}
+		members = append(members, newServiceMember(service))
+	}
+
+	listWorkloads := apiclient.ListWorkloads(ctx, &apphubpb.ListWorkloadsRequest{Parent: appName})
+	for {
+		workload, err := listWorkloads.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workloads: %w", err)
+		}
+		members = append(members, newWorkloadMember(workload))
+	}
+	return members, nil
+}
+
+func newServiceMember(service *apphubpb.Service) ApplicationMember {
+	return ApplicationMember{
+		ID:               getAssetShortName(service.GetName()),
+		Name:             service.GetName(),
+		DisplayName:      service.GetDisplayName(),
+		AppHubType:       "discoveredService",
+		DiscoveredName:   service.GetDiscoveredService(),
+		URI:              service.GetServiceReference().GetUri(),
+		Project:          service.GetServiceProperties().GetGcpProject(),
+		Location:         service.GetServiceProperties().GetLocation(),
+		AttributeSummary: newAttributeSummary(service.GetAttributes()),
+		registration:     service,
+	}
+}
+
+func newWorkloadMember(workload *apphubpb.Workload) ApplicationMember {
+	return ApplicationMember{
+		ID:               getAssetShortName(workload.GetName()),
+		Name:             workload.GetName(),
+		DisplayName:      workload.GetDisplayName(),
+		AppHubType:       "discoveredWorkload",
+		DiscoveredName:   workload.GetDiscoveredWorkload(),
+		URI:              workload.GetWorkloadReference().GetUri(),
+		Project:          workload.GetWorkloadProperties().GetGcpProject(),
+		Location:         workload.GetWorkloadProperties().GetLocation(),
+		AttributeSummary: newAttributeSummary(workload.GetAttributes()),
+		registration:     workload,
+	}
+}
+
+// enrichMembers sets the CAIS labels and tags of the underlying resource of each member
+func enrichMembers(members []ApplicationMember) error {
+	var projects, locations []string
+	for _, m := range members {
+		projects = append(projects, m.Project)
+		if m.Location != "" && !slices.Contains(locations, m.Location) {
+			locations = append(locations, m.Location)
+		}
+	}
+	if len(locations) == 0 {
+		return nil
+	}
+
+	assets, err := searchAssetsByURI(projects, locations)
+	if err != nil {
+		return err
+	}
+	for i := range members {
+		if asset, ok := assets[members[i].URI]; ok {
+			members[i].Labels = asset.GetLabels()
+			members[i].Tags = getAssetTags(asset)
+		}
+	}
+	return nil
+}
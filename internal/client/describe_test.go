@@ -0,0 +1,80 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"slices"
+	"testing"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+)
+
+func TestNewServiceMember(t *testing.T) {
+	service := &apphubpb.Service{
+		Name:              "projects/host/locations/us-west1/applications/shop/services/checkout",
+		DiscoveredService: "projects/host/locations/us-west1/discoveredServices/abc",
+		ServiceReference:  &apphubpb.ServiceReference{Uri: "//run.googleapis.com/projects/p1/locations/us-west1/services/checkout"},
+		ServiceProperties: &apphubpb.ServiceProperties{GcpProject: "projects/p1", Location: "us-west1"},
+		Attributes: &apphubpb.Attributes{
+			Environment: &apphubpb.Environment{Type: apphubpb.Environment_PRODUCTION},
+		},
+	}
+
+	got := newServiceMember(service)
+	if got.ID != "checkout" || got.AppHubType != "discoveredService" || got.Project != "projects/p1" {
+		t.Errorf("newServiceMember() = %+v", got)
+	}
+	if got.URI != service.GetServiceReference().GetUri() || got.Environment != "PRODUCTION" {
+		t.Errorf("newServiceMember() = %+v", got)
+	}
+}
+
+func TestEnrichMembers(t *testing.T) {
+	var searchedLocations []string
+	teamKey, teamValue := "123/team", "123/team/payments"
+	searchAssetsFunc = func(parent, labelKey, labelValue, tagKey, tagValue, contains string, locations, assetTypes []string) ([]*assetpb.ResourceSearchResult, error) {
+		searchedLocations = locations
+		return []*assetpb.ResourceSearchResult{
+			{
+				Name:      "//run.googleapis.com/projects/p1/locations/us-west1/services/checkout",
+				AssetType: "run.googleapis.com/Service",
+				Labels:    map[string]string{"appid": "shop"},
+				Tags: []*assetpb.Tag{
+					{TagKey: &teamKey, TagValue: &teamValue},
+				},
+			},
+		}, nil
+	}
+	defer func() { searchAssetsFunc = searchAssets }()
+
+	members := []ApplicationMember{
+		{URI: "//run.googleapis.com/projects/p1/locations/us-west1/services/checkout", Project: "projects/p1", Location: "us-west1"},
+		{URI: "//storage.googleapis.com/bucket", Project: "projects/p1", Location: "us"},
+	}
+	if err := enrichMembers(members); err != nil {
+		t.Fatalf("enrichMembers() error = %v", err)
+	}
+
+	if !slices.Equal(searchedLocations, []string{"us-west1", "us"}) {
+		t.Errorf("enrichMembers() searched %v", searchedLocations)
+	}
+	if members[0].Labels["appid"] != "shop" || members[0].Tags["team"] != "payments" {
+		t.Errorf("members[0] = %+v", members[0])
+	}
+	if members[1].Labels != nil || members[1].Tags != nil {
+		t.Errorf("members[1] = %+v", members[1])
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"internal/clilog"
+	"regexp"
+	"slices"
+	"strings"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	"golang.org/x/sync/errgroup"
+)
+
+// memberSelector selects the services and workloads of an application by
+// ID, resource URI or the CAIS labels of the underlying resource
+type memberSelector struct {
+	ids    []string
+	uris   []string
+	labels map[string]*regexp.Regexp
+}
+
+func newMemberSelector(ids, uris, labels []string) (*memberSelector, error) {
+	if len(ids) == 0 && len(uris) == 0 && len(labels) == 0 {
+		return nil, fmt.Errorf("at least one id, uri or label selector is required")
+	}
+	s := &memberSelector{ids: ids, uris: uris, labels: make(map[string]*regexp.Regexp)}
+	for _, label := range labels {
+		key, value, found := strings.Cut(label, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid label selector %s, must be of the format key=value", label)
+		}
+		re, err := compilePattern(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector: %w", err)
+		}
+		s.labels[key] = re
+	}
+	return s, nil
+}
+
+// matches returns true if a member is selected by any id, uri or label
+func (s *memberSelector) matches(m ApplicationMember) bool {
+	if slices.Contains(s.ids, m.ID) || slices.Contains(s.uris, m.URI) {
+		return true
+	}
+	for key, re := range s.labels {
+		if value, ok := m.Labels[key]; ok && re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// newMemberRegistration returns the registration of an application member,
+// the service or workload it was listed from
+func newMemberRegistration(m ApplicationMember) *registration {
+	r := &registration{}
+	switch registered := m.registration.(type) {
+	case *apphubpb.Service:
+		r.service = registered
+	case *apphubpb.Workload:
+		r.workload = registered
+	default:
+		if m.AppHubType == "discoveredService" {
+			r.service = &apphubpb.Service{Name: m.Name}
+		} else {
+			r.workload = &apphubpb.Workload{Name: m.Name}
+		}
+	}
+	return r
+}
+
+// DetachMembers removes the selected services and workloads from an
+// application, leaving the application in place. Members are selected by ID,
+// resource URI or CAIS labels of the form key=value. The application is saved
+// to the snapshot path before any member is removed. The selected members are
+// returned; with dryRun nothing is removed.
+func DetachMembers(managementProject, name string, locations, ids, uris, labels []string, snapshot string,
+	includeUnmanaged, dryRun bool,
+) ([]ApplicationMember, error) {
+	selector, err := newMemberSelector(ids, uris, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	app, err := findApplication(apphubClient, managementProject, name, locations)
+	if err != nil {
+		return nil, err
+	}
+	if !isManagedApp(app) && !includeUnmanaged {
+		return nil, errUnmanaged(app.GetName())
+	}
+
+	members, err := listMembers(apphubClient, app.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if len(selector.labels) > 0 {
+		if err = enrichMembers(members); err != nil {
+			return nil, err
+		}
+	}
+
+	var selected []ApplicationMember
+	for _, m := range members {
+		if selector.matches(m) {
+			selected = append(selected, m)
+		}
+	}
+
+	if dryRun || len(selected) == 0 {
+		return selected, nil
+	}
+	if err = WriteSnapshot(managementProject, []string{app.GetName()}, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot, nothing was detached: %w", err)
+	}
+	return selected, detachMembers(apphubClient, selected)
+}
+
+// detachMembers deletes services and workloads concurrently
+func detachMembers(apiclient appHubClient, members []ApplicationMember) error {
+	ctx := context.Background()
+	logger := clilog.GetLogger()
+
+	g, _ := errgroup.WithContext(ctx)
+
+	// Set the concurrency limit
+	g.SetLimit(DEFAULT_DELETION_CONCURRENCY)
+
+	logger.Info("Starting detach...", "maxConcurrency", DEFAULT_DELETION_CONCURRENCY)
+
+	for _, member := range members {
+		memberCopy := member
+
+		g.Go(func() error {
+			logger.Info("Starting deletion...", "member", memberCopy.Name)
+
+			if err := deleteRegistration(apiclient, newMemberRegistration(memberCopy)); err != nil {
+				return err
+			}
+
+			logger.Info("Member successfully detached.", "member", memberCopy.Name)
+			return nil
+		})
+	}
+
+	// Wait for all goroutines to finish
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	logger.Info("All members successfully detached.")
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+)
+
+func TestMemberSelector(t *testing.T) {
+	checkout := ApplicationMember{
+		ID:  "checkout",
+		URI: "//run.googleapis.com/projects/p1/locations/us-west1/services/checkout",
+	}
+	sandbox := ApplicationMember{
+		ID:     "cart",
+		URI:    "//run.googleapis.com/projects/p1/locations/us-west1/services/cart",
+		Labels: map[string]string{"env": "sandbox-2"},
+	}
+
+	tests := []struct {
+		name    string
+		ids     []string
+		uris    []string
+		labels  []string
+		member  ApplicationMember
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:   "By ID",
+			ids:    []string{"checkout"},
+			member: checkout,
+			want:   true,
+		},
+		{
+			name:   "By URI",
+			uris:   []string{checkout.URI},
+			member: checkout,
+			want:   true,
+		},
+		{
+			name:   "By label glob",
+			labels: []string{"env=sandbox*"},
+			member: sandbox,
+			want:   true,
+		},
+		{
+			name:   "Label without resource labels",
+			labels: []string{"env=sandbox*"},
+			member: checkout,
+			want:   false,
+		},
+		{
+			name:    "No selector",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid label",
+			labels:  []string{"env"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := newMemberSelector(tt.ids, tt.uris, tt.labels)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newMemberSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := selector.matches(tt.member); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetachedMemberJournalsRegistration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	SetJournal(path, "run-1")
+	defer SetJournal("", "")
+
+	member := newServiceMember(&apphubpb.Service{
+		Name:              "projects/p/locations/us-west1/applications/shop/services/checkout",
+		DisplayName:       "checkout",
+		DiscoveredService: "projects/p/locations/us-west1/discoveredServices/ds-1",
+	})
+	r := newMemberRegistration(member)
+	if r.service == nil || r.service.GetName() != member.Name {
+		t.Fatalf("newMemberRegistration() = %+v, want the listed service", r)
+	}
+	recordMutation("DELETE_SERVICE", r.service.GetName(), r.service, nil)
+
+	entries, err := ReadJournal(path)
+	if err != nil {
+		t.Fatalf("ReadJournal() error = %v", err)
+	}
+	if len(entries) != 1 || !strings.Contains(string(entries[0].Before), "discoveredServices/ds-1") {
+		t.Errorf("ReadJournal() = %+v, want the detached service as the before state", entries)
+	}
+}
@@ -0,0 +1,319 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"internal/clilog"
+	"slices"
+	"strings"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	"cloud.google.com/go/auth/credentials"
+	"cloud.google.com/go/iam/apiv1/iampb"
+	resourcemanager "cloud.google.com/go/resourcemanager/apiv3"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v3"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/serviceusage/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DoctorCheck is the outcome of a setup prerequisite check
+type DoctorCheck struct {
+	Name string `json:"name"`
+	// Status is one of PASS, WARN, FAIL or SKIP
+	Status      string `json:"status"`
+	Detail      string `json:"detail,omitempty"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// apphubPermissions are the permissions of roles/apphub.admin the tool uses
+var apphubPermissions = []string{
+	"apphub.applications.create",
+	"apphub.applications.delete",
+	"apphub.applications.list",
+	"apphub.applications.update",
+	"apphub.discoveredServices.list",
+	"apphub.discoveredWorkloads.list",
+	"apphub.services.create",
+	"apphub.services.delete",
+	"apphub.workloads.create",
+	"apphub.workloads.delete",
+}
+
+// cloudAssetPermissions are the permissions of roles/cloudasset.viewer the tool uses
+var cloudAssetPermissions = []string{
+	"cloudasset.assets.searchAllResources",
+}
+
+var (
+	detectCredentialsFunc   = detectCredentials
+	getServiceStateFunc     = getServiceState
+	testPermissionsFunc     = testPermissions
+	getFolderCapabilityFunc = getFolderCapability
+)
+
+// RunDoctor checks the prerequisites of the tool: credentials, enabled APIs,
+// IAM permissions on the management project and parent, the host project or
+// folder setup and App Hub reads in the locations. Every check runs, a failed
+// check does not stop the others.
+func RunDoctor(parent, managementProject string, locations []string) []DoctorCheck {
+	checks := []DoctorCheck{checkCredentials()}
+	for _, service := range []string{"apphub.googleapis.com", "cloudasset.googleapis.com"} {
+		checks = append(checks, checkService(managementProject, service))
+	}
+	checks = append(checks, checkPermissions("projects/"+managementProject, "roles/apphub.admin", apphubPermissions))
+	if parent != "" {
+		checks = append(checks, checkPermissions(parent, "roles/cloudasset.viewer", cloudAssetPermissions))
+	}
+
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return append(checks, DoctorCheck{
+			Name:        "App Hub client",
+			Status:      "FAIL",
+			Detail:      err.Error(),
+			Remediation: "gcloud auth application-default login",
+		})
+	}
+	defer closeAppHubClient(apphubClient)
+
+	checks = append(checks, checkHostProject(apphubClient, parent, managementProject)...)
+	return append(checks, checkAppHubReads(apphubClient, managementProject, locations)...)
+}
+
+func checkCredentials() DoctorCheck {
+	check := DoctorCheck{Name: "Application Default Credentials"}
+	if err := detectCredentialsFunc(); err != nil {
+		check.Status, check.Detail = "FAIL", err.Error()
+		check.Remediation = "gcloud auth application-default login"
+		return check
+	}
+	check.Status = "PASS"
+	return check
+}
+
+func checkService(projectID, service string) DoctorCheck {
+	check := DoctorCheck{Name: fmt.Sprintf("%s enabled on %s", service, projectID)}
+	state, err := getServiceStateFunc(projectID, service)
+	switch {
+	case err != nil:
+		check.Status, check.Detail = "FAIL", err.Error()
+		check.Remediation = "grant serviceusage.services.get or check the project ID"
+	case state != "ENABLED":
+		check.Status, check.Detail = "FAIL", "state is "+state
+		check.Remediation = fmt.Sprintf("gcloud services enable %s --project=%s", service, projectID)
+	default:
+		check.Status = "PASS"
+	}
+	return check
+}
+
+// checkPermissions tests the permissions of a role on a project or folder
+func checkPermissions(resource, role string, permissions []string) DoctorCheck {
+	check := DoctorCheck{Name: fmt.Sprintf("%s permissions on %s", role, resource)}
+	granted, err := testPermissionsFunc(resource, permissions)
+	if err != nil {
+		check.Status, check.Detail = "FAIL", err.Error()
+		return check
+	}
+
+	var missing []string
+	for _, permission := range permissions {
+		if !slices.Contains(granted, permission) {
+			missing = append(missing, permission)
+		}
+	}
+	if len(missing) == 0 {
+		check.Status = "PASS"
+		return check
+	}
+
+	check.Status, check.Detail = "FAIL", "missing "+strings.Join(missing, ", ")
+	if folder, ok := strings.CutPrefix(resource, "folders/"); ok {
+		check.Remediation = fmt.Sprintf("gcloud resource-manager folders add-iam-policy-binding %s --member=user:$EMAIL --role=%s",
+			folder, role)
+	} else {
+		check.Remediation = fmt.Sprintf("gcloud projects add-iam-policy-binding %s --member=user:$EMAIL --role=%s",
+			strings.TrimPrefix(resource, "projects/"), role)
+	}
+	return check
+}
+
+// checkHostProject checks that the management project is a host project, not
+// a service project of another one, that a parent project is attached to it
+// and that a parent folder is enabled for app management
+func checkHostProject(apiclient appHubClient, parent, managementProject string) []DoctorCheck {
+	ctx := context.Background()
+	check := DoctorCheck{Name: fmt.Sprintf("%s is a host project", managementProject)}
+
+	response, err := apiclient.LookupServiceProjectAttachment(ctx, &apphubpb.LookupServiceProjectAttachmentRequest{
+		Name: fmt.Sprintf("projects/%s/locations/global", managementProject),
+	})
+	if st, ok := status.FromError(err); err != nil && (!ok || st.Code() != codes.NotFound) {
+		check.Status, check.Detail = "FAIL", err.Error()
+		return []DoctorCheck{check}
+	}
+	if host := getNameSegment(response.GetServiceProjectAttachment().GetName(), "projects"); host != "" && host != managementProject {
+		check.Status = "FAIL"
+		check.Detail = fmt.Sprintf("attached as a service project to host project %s", host)
+		check.Remediation = "use the host project as the management project"
+		return []DoctorCheck{check}
+	}
+
+	attached, err := listAttachedProjects(apiclient, managementProject)
+	if err != nil {
+		check.Status, check.Detail = "FAIL", err.Error()
+		return []DoctorCheck{check}
+	}
+	// every attachment is indexed twice, by project name and by ID
+	check.Status, check.Detail = "PASS", fmt.Sprintf("%d service projects attached", len(attached)/2)
+	checks := []DoctorCheck{check}
+
+	if project, ok := strings.CutPrefix(parent, "projects/"); ok && project != managementProject {
+		check := DoctorCheck{Name: fmt.Sprintf("%s is attached to %s", project, managementProject)}
+		if attached[parent] {
+			check.Status = "PASS"
+		} else {
+			check.Status = "FAIL"
+			check.Remediation = fmt.Sprintf("apphub-app-creator apps attach-projects --management-project %s --projects %s",
+				managementProject, project)
+		}
+		checks = append(checks, check)
+	}
+
+	if folder, ok := strings.CutPrefix(parent, "folders/"); ok {
+		check := DoctorCheck{Name: fmt.Sprintf("%s is enabled for app management", parent)}
+		enabled, err := getFolderCapabilityFunc(folder, "app-management")
+		switch {
+		case err != nil:
+			check.Status, check.Detail = "FAIL", err.Error()
+		case enabled:
+			check.Status = "PASS"
+		case len(attached) > 0:
+			check.Status = "WARN"
+			check.Detail = "only resources of the attached service projects are discovered"
+			check.Remediation = "https://cloud.google.com/app-hub/docs/set-up-app-hub-folder"
+		default:
+			check.Status = "FAIL"
+			check.Detail = "the folder is not enabled and no service projects are attached"
+			check.Remediation = "https://cloud.google.com/app-hub/docs/set-up-app-hub-folder"
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkAppHubReads lists applications and discovered services in each
+// location, global when none is set
+func checkAppHubReads(apiclient appHubClient, managementProject string, locations []string) []DoctorCheck {
+	ctx := context.Background()
+
+	if len(locations) == 0 {
+		locations = []string{"global"}
+	}
+	if slices.Contains(locations, ALL_LOCATIONS) {
+		var err error
+		if locations, err = listLocationsFunc(apiclient, managementProject); err != nil {
+			return []DoctorCheck{{Name: "App Hub locations", Status: "FAIL", Detail: err.Error()}}
+		}
+	}
+
+	var checks []DoctorCheck
+	for _, location := range locations {
+		check := DoctorCheck{Name: fmt.Sprintf("App Hub reads in %s", location), Status: "PASS"}
+		parent := fmt.Sprintf("projects/%s/locations/%s", managementProject, location)
+
+		if _, err := apiclient.ListApplications(ctx, &apphubpb.ListApplicationsRequest{Parent: parent}).Next(); err != nil && err != iterator.Done {
+			check.Status, check.Detail = "FAIL", err.Error()
+		} else if _, err := apiclient.ListDiscoveredServices(ctx, &apphubpb.ListDiscoveredServicesRequest{Parent: parent}).Next(); err != nil && err != iterator.Done {
+			check.Status, check.Detail = "FAIL", err.Error()
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func detectCredentials() error {
+	_, err := credentials.DetectDefault(&credentials.DetectOptions{
+		Scopes: []string{"https://www.googleapis.com/auth/cloud-platform"},
+	})
+	return err
+}
+
+// getServiceState returns the state of a service in a project, ENABLED or DISABLED
+func getServiceState(projectID, service string) (string, error) {
+	ctx := context.Background()
+	logger := clilog.GetLogger()
+
+	serviceUsage, err := serviceusage.NewService(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to create service usage client: %w", err)
+	}
+	s, err := serviceUsage.Services.Get(fmt.Sprintf("projects/%s/services/%s", projectID, service)).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to get service %s: %w", service, err)
+	}
+	logger.Info("Service state", "project", projectID, "service", service, "state", s.State)
+	return s.State, nil
+}
+
+// testPermissions returns the permissions the caller has on a project or folder
+func testPermissions(resource string, permissions []string) ([]string, error) {
+	ctx := context.Background()
+	req := &iampb.TestIamPermissionsRequest{Resource: resource, Permissions: permissions}
+
+	if strings.HasPrefix(resource, "folders/") {
+		foldersClient, err := resourcemanager.NewFoldersClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create folders client: %w", err)
+		}
+		defer foldersClient.Close()
+		resp, err := foldersClient.TestIamPermissions(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to test permissions on %s: %w", resource, err)
+		}
+		return resp.GetPermissions(), nil
+	}
+
+	projectsClient, err := resourcemanager.NewProjectsClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create projects client: %w", err)
+	}
+	defer projectsClient.Close()
+	resp, err := projectsClient.TestIamPermissions(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to test permissions on %s: %w", resource, err)
+	}
+	return resp.GetPermissions(), nil
+}
+
+// getFolderCapability returns whether a Resource Manager capability is
+// enabled on a folder
+func getFolderCapability(folder, capability string) (bool, error) {
+	ctx := context.Background()
+
+	crm, err := cloudresourcemanager.NewService(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to create resource manager client: %w", err)
+	}
+	c, err := crm.Folders.Capabilities.Get(fmt.Sprintf("folders/%s/capabilities/%s", folder, capability)).Do()
+	if err != nil {
+		return false, fmt.Errorf("failed to get capability %s of folder %s: %w", capability, folder, err)
+	}
+	return c.Value, nil
+}
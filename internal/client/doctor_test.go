@@ -0,0 +1,87 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckPermissions(t *testing.T) {
+	testPermissionsFunc = func(resource string, permissions []string) ([]string, error) {
+		if resource == "folders/123" {
+			return nil, nil
+		}
+		return permissions, nil
+	}
+	defer func() { testPermissionsFunc = testPermissions }()
+
+	if got := checkPermissions("projects/host", "roles/apphub.admin", apphubPermissions); got.Status != "PASS" {
+		t.Errorf("checkPermissions() = %+v, want PASS", got)
+	}
+
+	got := checkPermissions("folders/123", "roles/cloudasset.viewer", cloudAssetPermissions)
+	if got.Status != "FAIL" || !strings.Contains(got.Detail, "cloudasset.assets.searchAllResources") {
+		t.Errorf("checkPermissions() = %+v, want the missing permission", got)
+	}
+	if !strings.HasPrefix(got.Remediation, "gcloud resource-manager folders add-iam-policy-binding 123 ") {
+		t.Errorf("checkPermissions() remediation = %s", got.Remediation)
+	}
+}
+
+func TestRunDoctor(t *testing.T) {
+	detectCredentialsFunc = func() error { return nil }
+	getServiceStateFunc = func(projectID, service string) (string, error) {
+		if service == "cloudasset.googleapis.com" {
+			return "DISABLED", nil
+		}
+		return "ENABLED", nil
+	}
+	testPermissionsFunc = func(resource string, permissions []string) ([]string, error) {
+		return permissions, nil
+	}
+	getAppHubClientFunc = func() (appHubClient, error) {
+		return nil, errors.New("no credentials")
+	}
+	defer func() {
+		detectCredentialsFunc = detectCredentials
+		getServiceStateFunc = getServiceState
+		testPermissionsFunc = testPermissions
+		getAppHubClientFunc = getAppHubClient
+	}()
+
+	checks := RunDoctor("projects/resources", "host", nil)
+
+	want := []struct{ name, status string }{
+		{"Application Default Credentials", "PASS"},
+		{"apphub.googleapis.com enabled on host", "PASS"},
+		{"cloudasset.googleapis.com enabled on host", "FAIL"},
+		{"roles/apphub.admin permissions on projects/host", "PASS"},
+		{"roles/cloudasset.viewer permissions on projects/resources", "PASS"},
+		{"App Hub client", "FAIL"},
+	}
+	if len(checks) != len(want) {
+		t.Fatalf("RunDoctor() = %+v, want %d checks", checks, len(want))
+	}
+	for i, w := range want {
+		if checks[i].Name != w.name || checks[i].Status != w.status {
+			t.Errorf("RunDoctor() check %d = %+v, want %s %s", i, checks[i], w.name, w.status)
+		}
+	}
+	if checks[2].Remediation != "gcloud services enable cloudasset.googleapis.com --project=host" {
+		t.Errorf("RunDoctor() remediation = %s", checks[2].Remediation)
+	}
+}
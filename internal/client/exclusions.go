@@ -0,0 +1,250 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"internal/clilog"
+	"regexp"
+	"slices"
+	"strings"
+
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+	"gopkg.in/yaml.v3"
+)
+
+// ExcludedAsset is an asset that was skipped because it matched an exclusion
+type ExcludedAsset struct {
+	Name   string
+	Reason string
+}
+
+// exclusions describes assets that must never be grouped into applications.
+// Values are globs unless enclosed in slashes, like the match criteria of rules.
+type exclusions struct {
+	Projects   []string `yaml:"projects,omitempty"`
+	AssetTypes []string `yaml:"assetTypes,omitempty"`
+	Labels     []string `yaml:"labels,omitempty"`
+	Namespaces []string `yaml:"namespaces,omitempty"`
+	Names      []string `yaml:"names,omitempty"`
+
+	// postFilterOnly skips the NOT clauses in the CAIS query so that every
+	// excluded asset is returned and can be listed in the report
+	postFilterOnly bool
+
+	projects   []*regexp.Regexp
+	assetTypes []*regexp.Regexp
+	labels     map[string]*regexp.Regexp
+	namespaces []*regexp.Regexp
+	names      []*regexp.Regexp
+	projectIDs map[string]string
+	excluded   []ExcludedAsset
+}
+
+// config is the structure of the file passed with --config
+type config struct {
+	Exclusions exclusions `yaml:"exclusions,omitempty"`
+}
+
+var assetExclusions = newExclusions()
+
+func newExclusions() *exclusions {
+	e := &exclusions{Namespaces: append([]string{}, GKE_EXCLUSION_NAMESPACES...)}
+	_ = e.compile()
+	return e
+}
+
+// SetExclusions configures the assets that are skipped by every CAIS search.
+// Exclusions read from the config file are merged with those passed as flags.
+// Labels are of the form key=value. When reportOnly is set, exclusions are
+// applied after the search so that every excluded asset can be reported.
+func SetExclusions(configData []byte, projects, assetTypes, labels, namespaces, names []string,
+	reportOnly bool,
+) error {
+	e := newExclusions()
+
+	if len(configData) > 0 {
+		c := config{}
+		if err := yaml.Unmarshal(configData, &c); err != nil {
+			return fmt.Errorf("failed to parse config: %w", err)
+		}
+		e.Projects = append(e.Projects, c.Exclusions.Projects...)
+		e.AssetTypes = append(e.AssetTypes, c.Exclusions.AssetTypes...)
+		e.Labels = append(e.Labels, c.Exclusions.Labels...)
+		e.Namespaces = append(e.Namespaces, c.Exclusions.Namespaces...)
+		e.Names = append(e.Names, c.Exclusions.Names...)
+	}
+
+	e.Projects = append(e.Projects, projects...)
+	e.AssetTypes = append(e.AssetTypes, assetTypes...)
+	e.Labels = append(e.Labels, labels...)
+	e.Namespaces = append(e.Namespaces, namespaces...)
+	e.Names = append(e.Names, names...)
+	e.postFilterOnly = reportOnly
+
+	if err := e.compile(); err != nil {
+		return err
+	}
+	assetExclusions = e
+	return nil
+}
+
+// GetExcludedAssets returns the assets skipped by the searches run so far
+func GetExcludedAssets() []ExcludedAsset {
+	return assetExclusions.excluded
+}
+
+// resetExcludedAssets forgets the excluded assets so that a long running
+// process reports only those of its current run
+func resetExcludedAssets() {
+	assetExclusions.excluded = nil
+}
+
+func (e *exclusions) compile() (err error) {
+	if e.projects, err = compilePatterns(e.Projects); err != nil {
+		return fmt.Errorf("invalid project exclusion: %w", err)
+	}
+	if e.assetTypes, err = compilePatterns(e.AssetTypes); err != nil {
+		return fmt.Errorf("invalid asset type exclusion: %w", err)
+	}
+	if e.namespaces, err = compilePatterns(e.Namespaces); err != nil {
+		return fmt.Errorf("invalid namespace exclusion: %w", err)
+	}
+	if e.names, err = compilePatterns(e.Names); err != nil {
+		return fmt.Errorf("invalid name exclusion: %w", err)
+	}
+	e.labels = make(map[string]*regexp.Regexp)
+	for _, label := range e.Labels {
+		key, value, found := strings.Cut(label, "=")
+		if !found || key == "" {
+			return fmt.Errorf("invalid label exclusion %s, must be of the format key=value", label)
+		}
+		if e.labels[key], err = compilePattern(value); err != nil {
+			return fmt.Errorf("invalid label exclusion: %w", err)
+		}
+	}
+	e.projectIDs = make(map[string]string)
+	return nil
+}
+
+// queryClauses returns the exclusions that can be expressed in a CAIS query.
+// CAIS matches words, so user namespaces are only added, anchored to their
+// namespace path, to Kubernetes searches; other searches leave them to the
+// post filter so that every excluded asset is reported.
+func (e *exclusions) queryClauses(kubernetes bool) []string {
+	var clauses []string
+
+	var ns []string
+	for _, n := range e.Namespaces {
+		// system namespaces are always excluded in the query to keep the report readable
+		if slices.Contains(GKE_EXCLUSION_NAMESPACES, n) {
+			ns = append(ns, fmt.Sprintf("parentFullResourceName : \"%s\"", n))
+			continue
+		}
+		if e.postFilterOnly || !kubernetes {
+			continue
+		}
+		if isLiteralPattern(n) {
+			ns = append(ns, fmt.Sprintf("parentFullResourceName : \"/k8s/namespaces/%s\"", n))
+		}
+	}
+	if len(ns) > 0 {
+		clauses = append(clauses, fmt.Sprintf("NOT (%s)", strings.Join(ns, " OR ")))
+	}
+
+	if e.postFilterOnly {
+		return clauses
+	}
+
+	for _, label := range e.Labels {
+		key, value, _ := strings.Cut(label, "=")
+		if isLiteralPattern(key) && isLiteralPattern(value) {
+			clauses = append(clauses, fmt.Sprintf("NOT labels.%s:%s", key, value))
+		}
+	}
+	return clauses
+}
+
+// filterAssetTypes removes the excluded asset types from the search request
+func (e *exclusions) filterAssetTypes(assetTypes []string) []string {
+	if e.postFilterOnly || len(e.assetTypes) == 0 {
+		return assetTypes
+	}
+	var filtered []string
+	for _, assetType := range assetTypes {
+		if !matchAny(e.assetTypes, assetType) {
+			filtered = append(filtered, assetType)
+		}
+	}
+	// an empty list searches every asset type, leave it to the post filter instead
+	if len(filtered) == 0 {
+		return assetTypes
+	}
+	return filtered
+}
+
+// reason returns why an asset is excluded or an empty string if it is not
+func (e *exclusions) reason(asset *assetpb.ResourceSearchResult) string {
+	if matchAny(e.assetTypes, asset.GetAssetType()) {
+		return fmt.Sprintf("asset type %s is excluded", asset.GetAssetType())
+	}
+	if namespace := getAssetNamespace(asset); namespace != "" && matchAny(e.namespaces, namespace) {
+		return fmt.Sprintf("namespace %s is excluded", namespace)
+	}
+	for key, re := range e.labels {
+		if value, ok := asset.GetLabels()[key]; ok && re.MatchString(value) {
+			return fmt.Sprintf("label %s=%s is excluded", key, value)
+		}
+	}
+	if matchAny(e.names, getAssetShortName(asset.GetName())) {
+		return fmt.Sprintf("name %s is excluded", getAssetShortName(asset.GetName()))
+	}
+	if len(e.projects) > 0 {
+		projectNumber := strings.TrimPrefix(asset.GetProject(), "projects/")
+		if matchAny(e.projects, projectNumber) {
+			return fmt.Sprintf("project %s is excluded", projectNumber)
+		}
+		projectID, ok := e.projectIDs[asset.GetProject()]
+		if !ok {
+			projectID = getProjectIDFunc(asset.GetProject(), context.Background())
+			e.projectIDs[asset.GetProject()] = projectID
+		}
+		if matchAny(e.projects, projectID) {
+			return fmt.Sprintf("project %s is excluded", projectID)
+		}
+	}
+	return ""
+}
+
+// filter removes the excluded assets from the search results and records them
+func (e *exclusions) filter(assets []*assetpb.ResourceSearchResult) []*assetpb.ResourceSearchResult {
+	logger := clilog.GetLogger()
+	var filtered []*assetpb.ResourceSearchResult
+	for _, asset := range assets {
+		if reason := e.reason(asset); reason != "" {
+			logger.Info("Excluding asset", "assetName", asset.GetName(), "reason", reason)
+			e.excluded = append(e.excluded, ExcludedAsset{Name: asset.GetName(), Reason: reason})
+			continue
+		}
+		filtered = append(filtered, asset)
+	}
+	return filtered
+}
+
+// isLiteralPattern returns true if the pattern has no glob or regex syntax
+func isLiteralPattern(pattern string) bool {
+	return !strings.ContainsAny(pattern, "*?/")
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+)
+
+const testConfig = `
+exclusions:
+  projects: ["sandbox-*"]
+  labels: ["env=sandbox"]
+  namespaces: ["istio-system"]
+`
+
+func TestSetExclusions(t *testing.T) {
+	defer func() { assetExclusions = newExclusions() }()
+
+	if err := SetExclusions([]byte(testConfig), nil, nil, []string{"env"}, nil, nil, false); err == nil {
+		t.Errorf("SetExclusions() expected error for label without value")
+	}
+
+	if err := SetExclusions([]byte(testConfig), nil, []string{"storage.googleapis.com/*"},
+		[]string{"tier=dev*"}, nil, []string{"/-test$/"}, false); err != nil {
+		t.Fatalf("SetExclusions() error = %v", err)
+	}
+
+	query := strings.Join(assetExclusions.queryClauses(true), " AND ")
+	for _, want := range []string{`parentFullResourceName : "kube-system"`, `parentFullResourceName : "/k8s/namespaces/istio-system"`, "NOT labels.env:sandbox"} {
+		if !strings.Contains(query, want) {
+			t.Errorf("queryClauses() = %s, want it to contain %s", query, want)
+		}
+	}
+	// other searches must not drop assets whose project or cluster name contains a namespace
+	query = strings.Join(assetExclusions.queryClauses(false), " AND ")
+	if strings.Contains(query, "istio-system") || !strings.Contains(query, "kube-system") {
+		t.Errorf("queryClauses(false) = %s, want only the system namespaces", query)
+	}
+	if strings.Contains(query, "tier") {
+		t.Errorf("queryClauses() = %s, glob values must not be part of the query", query)
+	}
+
+	got := assetExclusions.filterAssetTypes([]string{"storage.googleapis.com/Bucket", "run.googleapis.com/Service"})
+	if len(got) != 1 || got[0] != "run.googleapis.com/Service" {
+		t.Errorf("filterAssetTypes() = %v", got)
+	}
+
+	if err := SetExclusions([]byte(testConfig), nil, nil, nil, nil, nil, true); err != nil {
+		t.Fatalf("SetExclusions() error = %v", err)
+	}
+	query = strings.Join(assetExclusions.queryClauses(true), " AND ")
+	if strings.Contains(query, "istio-system") || strings.Contains(query, "labels.env") {
+		t.Errorf("queryClauses() = %s, report only must leave exclusions to the post filter", query)
+	}
+}
+
+func TestExclusionsFilter(t *testing.T) {
+	getProjectIDFunc = func(project string, ctx context.Context) string {
+		if project == "projects/222" {
+			return "sandbox-orders"
+		}
+		return "prod-orders"
+	}
+	defer func() {
+		getProjectIDFunc = getProjectID
+		assetExclusions = newExclusions()
+	}()
+
+	if err := SetExclusions([]byte(testConfig), nil, nil, nil, nil, []string{"*-test"}, true); err != nil {
+		t.Fatalf("SetExclusions() error = %v", err)
+	}
+
+	assets := []*assetpb.ResourceSearchResult{
+		{
+			Name:    "//run.googleapis.com/projects/p/locations/us-west1/services/checkout",
+			Project: "projects/111",
+		},
+		{
+			Name:    "//run.googleapis.com/projects/p/locations/us-west1/services/checkout-test",
+			Project: "projects/111",
+		},
+		{
+			Name:    "//run.googleapis.com/projects/p/locations/us-west1/services/cart",
+			Project: "projects/111",
+			Labels:  map[string]string{"env": "sandbox"},
+		},
+		{
+			Name:    "//storage.googleapis.com/bucket",
+			Project: "projects/222",
+		},
+		{
+			Name:                   "//container.googleapis.com/projects/p/locations/us-west1/clusters/c/k8s/namespaces/istio-system/apps/deployments/istiod",
+			Project:                "projects/111",
+			ParentFullResourceName: "//container.googleapis.com/projects/p/locations/us-west1/clusters/c/k8s/namespaces/istio-system",
+		},
+	}
+
+	filtered := assetExclusions.filter(assets)
+	if len(filtered) != 1 || filtered[0].Name != assets[0].Name {
+		t.Errorf("filter() = %v, want only %s", filtered, assets[0].Name)
+	}
+
+	excluded := GetExcludedAssets()
+	wantReasons := []string{"name checkout-test", "label env=sandbox", "project sandbox-orders", "namespace istio-system"}
+	if len(excluded) != len(wantReasons) {
+		t.Fatalf("GetExcludedAssets() = %v", excluded)
+	}
+	for i, want := range wantReasons {
+		if !strings.Contains(excluded[i].Reason, want) {
+			t.Errorf("GetExcludedAssets()[%d].Reason = %s, want %s", i, excluded[i].Reason, want)
+		}
+	}
+}
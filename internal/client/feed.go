@@ -0,0 +1,311 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"internal/clilog"
+	"slices"
+	"strings"
+	"sync"
+
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var findRegistrationsFunc = findRegistrations
+
+// FeedResult is the outcome of a CAIS feed notification
+type FeedResult struct {
+	Asset     string `json:"asset"`
+	AssetType string `json:"assetType,omitempty"`
+	// Action is one of REGISTERED, MOVED, DETACHED, UNCHANGED or IGNORED
+	Action      string `json:"action"`
+	Application string `json:"application,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// Feed registers the assets of CAIS feed notifications in App Hub, one
+// asset at a time, using the applications chosen by a rules file
+type Feed struct {
+	// notifications are processed one at a time, the rules and exclusions
+	// cache project IDs
+	mu                sync.Mutex
+	managementProject string
+	locations         []string
+	appLocation       string
+	rules             *ruleSet
+	attributesData    []byte
+}
+
+// pubSubPush is the body of a Pub/Sub push request
+type pubSubPush struct {
+	Message struct {
+		Data      []byte `json:"data"`
+		MessageID string `json:"messageId"`
+	} `json:"message"`
+	Subscription string `json:"subscription"`
+}
+
+// NewFeed returns a feed that registers the assets of the locations in the
+// applications chosen by the rules. As with generate, the applications are
+// global when more than one location is set.
+func NewFeed(managementProject string, locations []string, rulesData, attributesData []byte) (*Feed, error) {
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("at least one location is required")
+	}
+	rs, err := parseRules(rulesData)
+	if err != nil {
+		return nil, err
+	}
+	appLocation := locations[0]
+	if len(locations) > 1 {
+		appLocation = "global"
+	}
+	return &Feed{
+		managementProject: managementProject,
+		locations:         locations,
+		appLocation:       appLocation,
+		rules:             rs,
+		attributesData:    attributesData,
+	}, nil
+}
+
+// ParseFeedNotification decodes a CAIS feed notification, either the Pub/Sub
+// push request delivering it or the TemporalAsset JSON itself
+func ParseFeedNotification(payload []byte) (*assetpb.TemporalAsset, error) {
+	push := pubSubPush{}
+	if err := json.Unmarshal(payload, &push); err != nil {
+		return nil, fmt.Errorf("failed to parse notification: %w", err)
+	}
+	if len(push.Message.Data) > 0 {
+		payload = push.Message.Data
+	}
+
+	temporalAsset := &assetpb.TemporalAsset{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(payload, temporalAsset); err != nil {
+		return nil, fmt.Errorf("failed to parse temporal asset: %w", err)
+	}
+	if temporalAsset.GetAsset().GetName() == "" {
+		return nil, fmt.Errorf("notification does not contain an asset")
+	}
+	return temporalAsset, nil
+}
+
+// Process registers, moves or detaches the asset of a notification. An
+// asset that matches a rule is registered in its application, or moved there
+// from another application. An asset that is deleted, or no longer matches
+// the rule its prior state matched, is detached. A resource App Hub has not
+// discovered, for example because its project is not attached to the
+// management project, is ignored. Errors are returned for failures worth
+// retrying.
+func (f *Feed) Process(temporalAsset *assetpb.TemporalAsset) (FeedResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	logger := clilog.GetLogger()
+
+	asset := newFeedSearchResult(temporalAsset.GetAsset())
+	result := FeedResult{Asset: asset.GetName(), AssetType: asset.GetAssetType(), Action: "IGNORED"}
+
+	logger.Info("Processing feed notification", "assetName", asset.GetName(), "assetType", asset.GetAssetType(),
+		"deleted", temporalAsset.GetDeleted())
+
+	if getAssetTypeInfo(asset.GetAssetType()) == nil {
+		result.Reason = "asset type is not supported"
+		return result, nil
+	}
+	if reason := assetExclusions.reason(asset); reason != "" {
+		result.Reason = reason
+		return result, nil
+	}
+
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return result, fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	if temporalAsset.GetDeleted() {
+		return f.detach(apphubClient, result, asset, "", "asset was deleted")
+	}
+
+	assetRegion, err := describeRegion(getAssetLocation(asset))
+	if err != nil {
+		result.Reason = fmt.Sprintf("location %s is not supported", asset.GetLocation())
+		return result, nil
+	}
+	if !f.watchesLocation(assetRegion) {
+		result.Reason = fmt.Sprintf("location %s is not watched", assetRegion)
+		return result, nil
+	}
+
+	i, appName, err := f.rules.evaluate(asset)
+	if err != nil {
+		// the asset left the application its prior state was grouped in
+		if temporalAsset.GetPriorAsset() == nil {
+			result.Reason = err.Error()
+			return result, nil
+		}
+		_, priorAppName, priorErr := f.rules.evaluate(newFeedSearchResult(temporalAsset.GetPriorAsset()))
+		if priorErr != nil {
+			result.Reason = err.Error()
+			return result, nil
+		}
+		return f.detach(apphubClient, result, asset, f.applicationName(priorAppName), err.Error())
+	}
+	r := &f.rules.Rules[i]
+	result.Application = f.applicationName(appName)
+
+	appHubType := identifyServiceOrWorkload(asset.GetAssetType())
+	discoveredName, err := lookupDiscoveredServiceOrWorkload(apphubClient, f.managementProject, assetRegion,
+		asset.GetName(), appHubType, asset)
+	if err != nil {
+		if !isNotDiscovered(err) {
+			return result, fmt.Errorf("failed to lookup discovered service or workload for %s: %w", asset.GetName(), err)
+		}
+		result.Reason = "resource is not discovered by App Hub"
+		unattached := checkServiceProjects(apphubClient, f.managementProject, []*assetpb.ResourceSearchResult{asset}, false)
+		if unattached[asset.GetProject()] {
+			result.Reason = fmt.Sprintf("project %s is not attached to the management project", asset.GetProject())
+		}
+		return result, nil
+	}
+
+	lookupLocations := []string{f.appLocation}
+	if f.appLocation != "global" {
+		lookupLocations = append(lookupLocations, "global")
+	}
+	registrations, err := findRegistrationsFunc(apphubClient, f.managementProject, lookupLocations, []string{discoveredName}, nil)
+	if err != nil {
+		return result, err
+	}
+	for _, registration := range registrations {
+		if registration.appName == result.Application {
+			result.Action = "UNCHANGED"
+			return result, nil
+		}
+	}
+	if len(registrations) > 0 && !registrations[0].managed && !toolProvenance.includeUnmanaged {
+		result.Reason = fmt.Sprintf("registered in application %s which was not created by apphub-app-creator",
+			registrations[0].appName)
+		return result, nil
+	}
+
+	attributesData := f.attributesData
+	if len(r.attributesData) > 0 {
+		attributesData = r.attributesData
+	}
+	if _, err = getOrCreateAppHubApplication(apphubClient, f.managementProject, f.appLocation, appName, attributesData,
+		getProvenanceMarker(r.Name)); isUnmanaged(err) {
+		result.Reason = err.Error()
+		return result, nil
+	} else if err != nil {
+		return result, fmt.Errorf("error creating application: %w", err)
+	}
+
+	if len(registrations) > 0 {
+		result.Reason = "moved from " + registrations[0].appName
+		if _, err = moveRegistration(apphubClient, registrations[0], result.Application); err != nil {
+			return result, err
+		}
+		result.Action = "MOVED"
+		return result, nil
+	}
+
+	if err = registerServiceWithApplication(apphubClient, f.managementProject, f.appLocation, appName, discoveredName,
+		getAssetShortName(asset.GetName()), appHubType, attributesData); err != nil {
+		return result, fmt.Errorf("error registering service: %w", err)
+	}
+	result.Action = "REGISTERED"
+	return result, nil
+}
+
+// detach removes the registrations of an asset, by resource URI, from the
+// application or, when it is empty, from every application the tool manages
+func (f *Feed) detach(apiclient appHubClient, result FeedResult, asset *assetpb.ResourceSearchResult,
+	appName, reason string,
+) (FeedResult, error) {
+	result.Action, result.Application, result.Reason = "UNCHANGED", appName, reason
+
+	lookupLocations := []string{f.appLocation}
+	if f.appLocation != "global" {
+		lookupLocations = append(lookupLocations, "global")
+	}
+	uri := fixResourceURI(asset.GetName(), asset)
+	registrations, err := findRegistrationsFunc(apiclient, f.managementProject, lookupLocations, nil, []string{uri})
+	if err != nil {
+		return result, err
+	}
+
+	for _, registration := range registrations {
+		if appName != "" && registration.appName != appName {
+			continue
+		}
+		if !registration.managed && !toolProvenance.includeUnmanaged {
+			continue
+		}
+		if err = deleteRegistration(apiclient, registration); err != nil {
+			return result, err
+		}
+		result.Action, result.Application = "DETACHED", registration.appName
+	}
+	return result, nil
+}
+
+// watchesLocation returns true if assets of the region belong to the feed
+func (f *Feed) watchesLocation(region string) bool {
+	if region == "global" {
+		return f.appLocation == "global"
+	}
+	return slices.Contains(f.locations, region)
+}
+
+func (f *Feed) applicationName(appName string) string {
+	return fmt.Sprintf("projects/%s/locations/%s/applications/%s", f.managementProject, f.appLocation, appName)
+}
+
+// newFeedSearchResult converts the asset of a feed notification to the
+// search result returned by CAIS for the same resource. Feed assets do not
+// carry tags, so rules matching tags do not match them.
+func newFeedSearchResult(asset *assetpb.Asset) *assetpb.ResourceSearchResult {
+	result := &assetpb.ResourceSearchResult{
+		Name:                   asset.GetName(),
+		AssetType:              asset.GetAssetType(),
+		Location:               asset.GetResource().GetLocation(),
+		ParentFullResourceName: asset.GetResource().GetParent(),
+	}
+	for _, ancestor := range asset.GetAncestors() {
+		if strings.HasPrefix(ancestor, "projects/") {
+			result.Project = ancestor
+			break
+		}
+	}
+
+	// Kubernetes resources keep their labels in the object metadata
+	data := asset.GetResource().GetData().GetFields()
+	labels := data["labels"].GetStructValue().GetFields()
+	if labels == nil {
+		labels = data["metadata"].GetStructValue().GetFields()["labels"].GetStructValue().GetFields()
+	}
+	if len(labels) > 0 {
+		result.Labels = make(map[string]string)
+		for key, value := range labels {
+			result.Labels[key] = value.GetStringValue()
+		}
+	}
+	return result
+}
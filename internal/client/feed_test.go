@@ -0,0 +1,243 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"testing"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testFeedRules = `
+rules:
+  - name: appid-label
+    match:
+      labels:
+        appid: "*"
+    app: '{{ index .Labels "appid" }}'
+`
+
+const testTemporalAsset = `{
+  "asset": {
+    "name": "//run.googleapis.com/projects/orders/locations/us-west1/services/checkout",
+    "assetType": "run.googleapis.com/Service",
+    "ancestors": ["projects/222", "folders/5432", "organizations/1234"],
+    "resource": {
+      "location": "us-west1",
+      "parent": "//cloudresourcemanager.googleapis.com/projects/222",
+      "data": {"labels": {"appid": "orders"}}
+    }
+  },
+  "priorAssetState": "PRESENT"
+}`
+
+func TestParseFeedNotification(t *testing.T) {
+	push := `{"message": {"data": "` + base64.StdEncoding.EncodeToString([]byte(testTemporalAsset)) +
+		`", "messageId": "1"}, "subscription": "projects/host/subscriptions/apphub-feed"}`
+
+	for name, payload := range map[string]string{"Pub/Sub push": push, "Temporal asset": testTemporalAsset} {
+		t.Run(name, func(t *testing.T) {
+			temporalAsset, err := ParseFeedNotification([]byte(payload))
+			if err != nil {
+				t.Fatalf("ParseFeedNotification() error = %v", err)
+			}
+			got := newFeedSearchResult(temporalAsset.GetAsset())
+			if got.GetProject() != "projects/222" || got.GetLocation() != "us-west1" ||
+				!reflect.DeepEqual(got.GetLabels(), map[string]string{"appid": "orders"}) {
+				t.Errorf("newFeedSearchResult() = %v", got)
+			}
+		})
+	}
+
+	if _, err := ParseFeedNotification([]byte(`{"window": {}}`)); err == nil {
+		t.Errorf("ParseFeedNotification() without an asset did not fail")
+	}
+}
+
+func TestNewFeedSearchResultKubernetes(t *testing.T) {
+	temporalAsset, err := ParseFeedNotification([]byte(`{"asset": {
+		"name": "//container.googleapis.com/projects/p/locations/us-west1/clusters/c/k8s/namespaces/shop/apps/deployments/cart",
+		"assetType": "apps.k8s.io/Deployment",
+		"resource": {
+			"location": "us-west1",
+			"parent": "//container.googleapis.com/projects/p/locations/us-west1/clusters/c/k8s/namespaces/shop",
+			"data": {"metadata": {"labels": {"app.kubernetes.io/name": "cart"}}}
+		}
+	}}`))
+	if err != nil {
+		t.Fatalf("ParseFeedNotification() error = %v", err)
+	}
+	got := newFeedSearchResult(temporalAsset.GetAsset())
+	if getAssetNamespace(got) != "shop" || got.GetLabels()["app.kubernetes.io/name"] != "cart" {
+		t.Errorf("newFeedSearchResult() = %v", got)
+	}
+}
+
+func TestFeedProcess(t *testing.T) {
+	mockClient := &mockAppHubClient{
+		lookupDiscoveredServiceFunc: func(ctx context.Context, req *apphubpb.LookupDiscoveredServiceRequest, opts ...gax.CallOption) (*apphubpb.LookupDiscoveredServiceResponse, error) {
+			return &apphubpb.LookupDiscoveredServiceResponse{
+				DiscoveredService: &apphubpb.DiscoveredService{Name: "projects/host/locations/us-west1/discoveredServices/ds-1"},
+			}, nil
+		},
+	}
+	getAppHubClientFunc = func() (appHubClient, error) { return mockClient, nil }
+	findRegistrationsFunc = func(apiclient appHubClient, projectID string, locations, discoveredNames, uris []string) ([]*registration, error) {
+		return []*registration{{
+			appName: "projects/host/locations/us-west1/applications/orders",
+			managed: true,
+			service: &apphubpb.Service{DiscoveredService: "projects/host/locations/us-west1/discoveredServices/ds-1"},
+		}}, nil
+	}
+	defer func() {
+		getAppHubClientFunc = getAppHubClient
+		findRegistrationsFunc = findRegistrations
+	}()
+
+	feed, err := NewFeed("host", []string{"us-west1"}, []byte(testFeedRules), nil)
+	if err != nil {
+		t.Fatalf("NewFeed() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		payload    string
+		wantAction string
+	}{
+		{
+			name:       "Already registered",
+			payload:    testTemporalAsset,
+			wantAction: "UNCHANGED",
+		},
+		{
+			name:       "Unsupported asset type",
+			payload:    `{"asset": {"name": "//storage.googleapis.com/bucket", "assetType": "storage.googleapis.com/Bucket"}}`,
+			wantAction: "IGNORED",
+		},
+		{
+			name: "Location not watched",
+			payload: `{"asset": {"name": "//run.googleapis.com/projects/orders/locations/us-east1/services/checkout",
+				"assetType": "run.googleapis.com/Service", "resource": {"location": "us-east1", "data": {"labels": {"appid": "orders"}}}}}`,
+			wantAction: "IGNORED",
+		},
+		{
+			name: "No rule matched",
+			payload: `{"asset": {"name": "//run.googleapis.com/projects/orders/locations/us-west1/services/checkout",
+				"assetType": "run.googleapis.com/Service", "resource": {"location": "us-west1"}}}`,
+			wantAction: "IGNORED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			temporalAsset, err := ParseFeedNotification([]byte(tt.payload))
+			if err != nil {
+				t.Fatalf("ParseFeedNotification() error = %v", err)
+			}
+			got, err := feed.Process(temporalAsset)
+			if err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			if got.Action != tt.wantAction {
+				t.Errorf("Process() = %+v, want %s", got, tt.wantAction)
+			}
+		})
+	}
+}
+
+func TestFeedProcessNotDiscovered(t *testing.T) {
+	var lookupErr error
+	getAppHubClientFunc = func() (appHubClient, error) {
+		return &mockAppHubClient{
+			lookupDiscoveredServiceFunc: func(ctx context.Context, req *apphubpb.LookupDiscoveredServiceRequest, opts ...gax.CallOption) (*apphubpb.LookupDiscoveredServiceResponse, error) {
+				return nil, lookupErr
+			},
+			lookupAttachmentFunc: func(ctx context.Context, req *apphubpb.LookupServiceProjectAttachmentRequest, opts ...gax.CallOption) (*apphubpb.LookupServiceProjectAttachmentResponse, error) {
+				return &apphubpb.LookupServiceProjectAttachmentResponse{}, nil
+			},
+		}, nil
+	}
+	getProjectIDFunc = func(project string, ctx context.Context) string {
+		return "orders"
+	}
+	attached := map[string]bool{}
+	listAttachedProjectsFunc = func(apiclient appHubClient, managementProject string) (map[string]bool, error) {
+		return attached, nil
+	}
+	defer func() {
+		getAppHubClientFunc = getAppHubClient
+		getProjectIDFunc = getProjectID
+		listAttachedProjectsFunc = listAttachedProjects
+	}()
+
+	feed, err := NewFeed("host", []string{"us-west1"}, []byte(testFeedRules), nil)
+	if err != nil {
+		t.Fatalf("NewFeed() error = %v", err)
+	}
+	temporalAsset, err := ParseFeedNotification([]byte(testTemporalAsset))
+	if err != nil {
+		t.Fatalf("ParseFeedNotification() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		lookupErr  error
+		attached   map[string]bool
+		wantReason string
+		wantErr    bool
+	}{
+		{
+			name:       "Project not attached",
+			lookupErr:  fmt.Errorf("%w: checkout", errServiceNotDiscovered),
+			attached:   map[string]bool{},
+			wantReason: "project projects/222 is not attached to the management project",
+		},
+		{
+			name:       "Not discovered",
+			lookupErr:  status.Error(codes.NotFound, "not found"),
+			attached:   map[string]bool{"projects/orders": true},
+			wantReason: "resource is not discovered by App Hub",
+		},
+		{
+			name:      "Transient error",
+			lookupErr: status.Error(codes.Unavailable, "unavailable"),
+			attached:  map[string]bool{"projects/orders": true},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookupErr, attached = tt.lookupErr, tt.attached
+			got, err := feed.Process(temporalAsset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Action != "IGNORED" || got.Reason != tt.wantReason {
+				t.Errorf("Process() = %+v, want IGNORED with reason %q", got, tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	"google.golang.org/api/iterator"
+)
+
+// GenerateJob is a generate run described by its options rather than flags,
+// used by the watch file and the HTTP API. Exactly one generate mode must be
+// set: labelKey, tagKey, contains, perK8sNamespace, perK8sAppLabel,
+// autoDetect, projectKeys or rules.
+type GenerateJob struct {
+	Name              string   `yaml:"name" json:"name,omitempty"`
+	Parent            string   `yaml:"parent" json:"parent"`
+	ManagementProject string   `yaml:"managementProject,omitempty" json:"managementProject,omitempty"`
+	Locations         []string `yaml:"locations" json:"locations"`
+	LabelKey          string   `yaml:"labelKey,omitempty" json:"labelKey,omitempty"`
+	LabelValue        string   `yaml:"labelValue,omitempty" json:"labelValue,omitempty"`
+	TagKey            string   `yaml:"tagKey,omitempty" json:"tagKey,omitempty"`
+	TagValue          string   `yaml:"tagValue,omitempty" json:"tagValue,omitempty"`
+	Contains          string   `yaml:"contains,omitempty" json:"contains,omitempty"`
+	PerK8sNamespace   bool     `yaml:"perK8sNamespace,omitempty" json:"perK8sNamespace,omitempty"`
+	NamespaceGroupBy  string   `yaml:"namespaceGroupBy,omitempty" json:"namespaceGroupBy,omitempty"`
+	PerK8sAppLabel    bool     `yaml:"perK8sAppLabel,omitempty" json:"perK8sAppLabel,omitempty"`
+	K8sAppLabels      []string `yaml:"k8sAppLabels,omitempty" json:"k8sAppLabels,omitempty"`
+	AutoDetect        bool     `yaml:"autoDetect,omitempty" json:"autoDetect,omitempty"`
+	ProjectKeys       []string `yaml:"projectKeys,omitempty" json:"projectKeys,omitempty"`
+	AppName           string   `yaml:"appName,omitempty" json:"appName,omitempty"`
+	// Rules and Attributes are paths to a rules file and an attributes file,
+	// read when the job is validated. The HTTP API passes the content of the
+	// files in RulesData and AttributesData instead.
+	Rules          string          `yaml:"rules,omitempty" json:"-"`
+	Attributes     string          `yaml:"attributes,omitempty" json:"-"`
+	RulesData      string          `yaml:"-" json:"rules,omitempty"`
+	AttributesData json.RawMessage `yaml:"-" json:"attributes,omitempty"`
+}
+
+// GeneratedApplication is an application generate creates, or would create,
+// with the services and workloads it registers
+type GeneratedApplication struct {
+	Name string `json:"name"`
+	// Action is set in a plan, one of CREATE, UPDATE, UNCHANGED or UNMANAGED
+	// for an existing application that generate is not allowed to modify. An
+	// apply sets UNMANAGED on the applications it skipped.
+	Action  string            `json:"action,omitempty"`
+	Members []GeneratedMember `json:"members"`
+}
+
+// GeneratedMember is a discovered service or workload grouped in an application
+type GeneratedMember struct {
+	ID         string `json:"id"`
+	AppHubType string `json:"appHubType"`
+	Asset      string `json:"asset"`
+	// Action is set in a plan, one of REGISTER, UNCHANGED, MOVE or SKIP
+	Action string `json:"action,omitempty"`
+	// Application is the application that holds the member when it is not the generated one
+	Application string `json:"application,omitempty"`
+}
+
+// GenerationPlan is what generate would change in App Hub
+type GenerationPlan struct {
+	Location     string                 `json:"location"`
+	Applications []GeneratedApplication `json:"applications"`
+	Unmatched    []string               `json:"unmatched,omitempty"`
+	Excluded     []ExcludedAsset        `json:"excluded,omitempty"`
+}
+
+// Validate checks the options of the job, defaults the management project to
+// the parent project and reads the rules and attributes files
+func (j *GenerateJob) Validate() error {
+	if j.Parent == "" {
+		return fmt.Errorf("parent is required")
+	}
+	if len(j.Locations) == 0 {
+		return fmt.Errorf("at least one location is required")
+	}
+	if j.ManagementProject == "" {
+		project, ok := strings.CutPrefix(j.Parent, "projects/")
+		if !ok {
+			return fmt.Errorf("managementProject is required when parent is a folder")
+		}
+		j.ManagementProject = project
+	}
+
+	var modes []string
+	for mode, set := range map[string]bool{
+		"label-key":         j.LabelKey != "",
+		"tag-key":           j.TagKey != "",
+		"contains":          j.Contains != "",
+		"per-k8s-namespace": j.PerK8sNamespace,
+		"per-k8s-app-label": j.PerK8sAppLabel,
+		"auto-detect":       j.AutoDetect,
+		"project-keys":      len(j.ProjectKeys) > 0,
+		"rules":             j.Rules != "" || j.RulesData != "",
+	} {
+		if set {
+			modes = append(modes, mode)
+		}
+	}
+	if len(modes) != 1 {
+		return fmt.Errorf("exactly one generate mode is required, found %d", len(modes))
+	}
+	if len(j.ProjectKeys) > 0 && j.AppName == "" {
+		return fmt.Errorf("appName is required with projectKeys")
+	}
+	if j.NamespaceGroupBy != "" && !j.PerK8sNamespace {
+		return fmt.Errorf("namespaceGroupBy is only supported with perK8sNamespace")
+	}
+	if err := ValidateNamespaceGroup(j.NamespaceGroupBy); err != nil {
+		return err
+	}
+	if len(j.K8sAppLabels) > 0 && !j.PerK8sAppLabel {
+		return fmt.Errorf("k8sAppLabels is only supported with perK8sAppLabel")
+	}
+
+	if j.Rules != "" {
+		data, err := os.ReadFile(j.Rules)
+		if err != nil {
+			return fmt.Errorf("failed to read rules: %w", err)
+		}
+		j.RulesData = string(data)
+	}
+	if j.RulesData != "" {
+		if _, err := parseRules([]byte(j.RulesData)); err != nil {
+			return err
+		}
+	}
+	if j.Attributes != "" {
+		data, err := os.ReadFile(j.Attributes)
+		if err != nil {
+			return fmt.Errorf("failed to read attributes: %w", err)
+		}
+		j.AttributesData = data
+	}
+	if len(j.AttributesData) > 0 {
+		if _, err := newAttributesFromBytes(j.AttributesData); err != nil {
+			return fmt.Errorf("failed to parse attributes: %w", err)
+		}
+	}
+	return nil
+}
+
+// Mode returns the generate flag of the job, recorded as its provenance
+func (j *GenerateJob) Mode() string {
+	switch {
+	case j.LabelKey != "":
+		return "label-key"
+	case j.TagKey != "":
+		return "tag-key"
+	case j.Contains != "":
+		return "contains"
+	case j.PerK8sNamespace:
+		return "per-k8s-namespace"
+	case j.PerK8sAppLabel:
+		return "per-k8s-app-label"
+	case j.AutoDetect:
+		return "auto-detect"
+	case len(j.ProjectKeys) > 0:
+		return "project-keys"
+	}
+	return "rules"
+}
+
+// Generate runs the job and returns the generated applications and, for
+// rules, the assets no rule matched. With reportOnly nothing is created.
+func (j *GenerateJob) Generate(reportOnly bool) (map[string][]string, []string, error) {
+	attributesData := []byte(j.AttributesData)
+
+	switch j.Mode() {
+	case "rules":
+		return GenerateFromRules(j.Parent, j.ManagementProject, j.Locations, []byte(j.RulesData), attributesData, reportOnly)
+	case "auto-detect":
+		generated, err := GenerateFromAll(j.Parent, j.ManagementProject, j.Locations, attributesData, reportOnly)
+		return generated, nil, err
+	case "per-k8s-namespace":
+		generated, err := GenerateAppsPerNamespace(j.Parent, j.ManagementProject, j.Locations, attributesData, j.NamespaceGroupBy,
+			reportOnly)
+		return generated, nil, err
+	case "per-k8s-app-label":
+		generated, err := GenerateKubernetesApps(j.Parent, j.ManagementProject, j.Locations, j.K8sAppLabels, attributesData,
+			reportOnly)
+		return generated, nil, err
+	case "project-keys":
+		generated, err := GenerateFromProject(j.Parent, j.ManagementProject, j.AppName, j.ProjectKeys, j.Locations,
+			attributesData, reportOnly)
+		return generated, nil, err
+	}
+	labelValue := j.LabelValue
+	if j.LabelKey != "" && labelValue == "" {
+		labelValue = "*"
+	}
+	generated, err := GenerateAppsAssetInventory(j.Parent, j.ManagementProject, j.LabelKey, labelValue, j.TagKey, j.TagValue,
+		j.Contains, j.Locations, attributesData, reportOnly)
+	return generated, nil, err
+}
+
+// appLocation returns the location of the applications of the job, global
+// when the job spans more than one location or groups namespaces across clusters
+func (j *GenerateJob) appLocation() string {
+	location := j.Locations[0]
+	if len(j.Locations) > 1 {
+		location = "global"
+	}
+	if j.Mode() == "per-k8s-namespace" {
+		return getNamespaceAppLocation(j.NamespaceGroupBy, location)
+	}
+	return location
+}
+
+// NewGeneratedApplications converts the applications returned by generate,
+// whose members are stored as their discovered id, type and asset name. The
+// applications the run skipped because the tool does not manage them are
+// marked UNMANAGED.
+func NewGeneratedApplications(generated map[string][]string) []GeneratedApplication {
+	applications := []GeneratedApplication{}
+	for name, values := range generated {
+		app := GeneratedApplication{Name: name, Members: []GeneratedMember{}}
+		if slices.Contains(GetUnmanagedApplications(), name) {
+			app.Action = "UNMANAGED"
+		}
+		for i := 0; i+2 < len(values); i += 3 {
+			app.Members = append(app.Members, GeneratedMember{ID: values[i], AppHubType: values[i+1], Asset: values[i+2]})
+		}
+		applications = append(applications, app)
+	}
+	sort.Slice(applications, func(i, k int) bool {
+		return applications[i].Name < applications[k].Name
+	})
+	return applications
+}
+
+// PlanGeneration runs the job without changing anything and compares the
+// generated applications with App Hub: the applications that would be
+// created and the members that would be registered or moved
+func PlanGeneration(job *GenerateJob) (*GenerationPlan, error) {
+	generated, unmatched, err := job.Generate(true)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &GenerationPlan{
+		Location:  job.appLocation(),
+		Unmatched: unmatched,
+		Excluded:  GetExcludedAssets(),
+	}
+
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	locations := []string{plan.Location}
+	if plan.Location != "global" {
+		locations = append(locations, "global")
+	}
+	existing, registered, err := listApplicationRegistrations(apphubClient, job.ManagementProject, locations)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, app := range NewGeneratedApplications(generated) {
+		appName := fmt.Sprintf("projects/%s/locations/%s/applications/%s", job.ManagementProject, plan.Location, app.Name)
+		plan.Applications = append(plan.Applications, planApplication(app, appName, existing, registered))
+	}
+	return plan, nil
+}
+
+// planApplication sets the action of an application and its members
+func planApplication(app GeneratedApplication, appName string, existing map[string]*apphubpb.Application,
+	registered map[string]*registration,
+) GeneratedApplication {
+	planned := GeneratedApplication{Name: app.Name, Action: "UNCHANGED"}
+	planned.Members = make([]GeneratedMember, len(app.Members))
+
+	current, exists := existing[appName]
+	switch {
+	case !exists:
+		planned.Action = "CREATE"
+	case !isManagedApp(current) && !toolProvenance.includeUnmanaged:
+		planned.Action = "UNMANAGED"
+	}
+
+	for i, member := range app.Members {
+		member.Action = "REGISTER"
+		if r, ok := registered[member.ID]; ok {
+			switch {
+			case r.appName == appName:
+				member.Action = "UNCHANGED"
+			case reassignRegistrations && (r.managed || toolProvenance.includeUnmanaged):
+				member.Action, member.Application = "MOVE", r.appName
+			default:
+				member.Action, member.Application = "SKIP", r.appName
+			}
+		}
+		if planned.Action == "UNCHANGED" && member.Action != "UNCHANGED" && member.Action != "SKIP" {
+			planned.Action = "UPDATE"
+		}
+		planned.Members[i] = member
+	}
+	return planned
+}
+
+// listApplicationRegistrations returns the applications of the locations by
+// name and their registrations by discovered service or workload id
+func listApplicationRegistrations(apiclient appHubClient, projectID string, locations []string) (
+	map[string]*apphubpb.Application, map[string]*registration, error,
+) {
+	ctx := context.Background()
+	applications := make(map[string]*apphubpb.Application)
+	registered := make(map[string]*registration)
+
+	for _, location := range locations {
+		listApplications := apiclient.ListApplications(ctx, &apphubpb.ListApplicationsRequest{
+			Parent: fmt.Sprintf("projects/%s/locations/%s", projectID, location),
+		})
+		for {
+			app, err := listApplications.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to list applications: %w", err)
+			}
+			applications[app.GetName()] = app
+
+			appRegistrations, err := listRegistrations(apiclient, app)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, r := range appRegistrations {
+				registered[getAssetShortName(r.member().DiscoveredName)] = r
+			}
+		}
+	}
+	return applications, registered, nil
+}
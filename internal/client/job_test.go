@@ -0,0 +1,100 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+)
+
+func TestGenerateJobValidate(t *testing.T) {
+	job := &GenerateJob{
+		Parent:         "projects/orders",
+		Locations:      []string{"us-west1"},
+		RulesData:      "rules:\n  - app: orders\n",
+		AttributesData: []byte(`{"criticality": {"type": "HIGH"}}`),
+	}
+	if err := job.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if job.ManagementProject != "orders" || job.Mode() != "rules" {
+		t.Errorf("Validate() job = %+v, mode %s", job, job.Mode())
+	}
+
+	job.RulesData = "rules: []"
+	if err := job.Validate(); err == nil {
+		t.Errorf("Validate() with invalid rules did not fail")
+	}
+}
+
+func TestNewGeneratedApplications(t *testing.T) {
+	got := NewGeneratedApplications(map[string][]string{
+		"payments": {"ds-2", "discoveredWorkload", "//sqladmin/pay-db"},
+		"orders":   {"ds-1", "discoveredService", "//run/checkout", "ds-3", "discoveredService", "//run/cart"},
+	})
+	want := []GeneratedApplication{
+		{Name: "orders", Members: []GeneratedMember{
+			{ID: "ds-1", AppHubType: "discoveredService", Asset: "//run/checkout"},
+			{ID: "ds-3", AppHubType: "discoveredService", Asset: "//run/cart"},
+		}},
+		{Name: "payments", Members: []GeneratedMember{
+			{ID: "ds-2", AppHubType: "discoveredWorkload", Asset: "//sqladmin/pay-db"},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewGeneratedApplications() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPlanApplication(t *testing.T) {
+	const (
+		orders   = "projects/host/locations/us-west1/applications/orders"
+		payments = "projects/host/locations/us-west1/applications/payments"
+		legacy   = "projects/host/locations/global/applications/legacy"
+	)
+	existing := map[string]*apphubpb.Application{
+		orders: {Name: orders, Description: MANAGED_MARKER},
+		legacy: {Name: legacy},
+	}
+	registered := map[string]*registration{
+		"ds-1": {appName: orders, managed: true},
+		"ds-2": {appName: legacy},
+	}
+	app := GeneratedApplication{Name: "orders", Members: []GeneratedMember{
+		{ID: "ds-1"}, {ID: "ds-2"}, {ID: "ds-3"},
+	}}
+
+	got := planApplication(app, orders, existing, registered)
+	if got.Action != "UPDATE" {
+		t.Errorf("planApplication() action = %s, want UPDATE", got.Action)
+	}
+	wantMembers := []GeneratedMember{
+		{ID: "ds-1", Action: "UNCHANGED"},
+		{ID: "ds-2", Action: "SKIP", Application: legacy},
+		{ID: "ds-3", Action: "REGISTER"},
+	}
+	if !reflect.DeepEqual(got.Members, wantMembers) {
+		t.Errorf("planApplication() members = %+v, want %+v", got.Members, wantMembers)
+	}
+
+	if got = planApplication(GeneratedApplication{Name: "payments"}, payments, existing, registered); got.Action != "CREATE" {
+		t.Errorf("planApplication() action = %s, want CREATE", got.Action)
+	}
+	if got = planApplication(GeneratedApplication{Name: "legacy"}, legacy, existing, registered); got.Action != "UNMANAGED" {
+		t.Errorf("planApplication() action = %s, want UNMANAGED", got.Action)
+	}
+}
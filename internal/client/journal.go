@@ -0,0 +1,386 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"internal/clilog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DEFAULT_JOURNAL is the journal written in the current directory
+const DEFAULT_JOURNAL = "apphub-journal.jsonl"
+
+// JournalEntry is a mutation of an App Hub resource. Before and After hold the
+// resource in the JSON representation of the App Hub API.
+type JournalEntry struct {
+	Timestamp string `json:"timestamp"`
+	RunID     string `json:"runId"`
+	// Action is one of CREATE_APPLICATION, UPDATE_APPLICATION, DELETE_APPLICATION,
+	// CREATE_SERVICE, DELETE_SERVICE, CREATE_WORKLOAD, DELETE_WORKLOAD or ATTACH_PROJECT
+	Action   string          `json:"action"`
+	Resource string          `json:"resource"`
+	Before   json.RawMessage `json:"before,omitempty"`
+	After    json.RawMessage `json:"after,omitempty"`
+}
+
+// UndoResult is the outcome of reversing a journal entry
+type UndoResult struct {
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+	// Status is one of UNDONE, MODIFIED, GONE, SKIPPED or FAILED
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// journal appends the mutations of a run to a JSONL file
+type journal struct {
+	mu        sync.Mutex
+	path      string
+	runID     string
+	mutations int
+}
+
+var mutationJournal = &journal{}
+
+// SetJournal records every mutation of the run to the journal at path. An
+// empty path disables the journal.
+func SetJournal(path, runID string) {
+	mutationJournal.mu.Lock()
+	defer mutationJournal.mu.Unlock()
+	mutationJournal.path = path
+	mutationJournal.runID = runID
+	mutationJournal.mutations = 0
+}
+
+// GetRunID returns the ID of the current run
+func GetRunID() string {
+	mutationJournal.mu.Lock()
+	defer mutationJournal.mu.Unlock()
+	return mutationJournal.runID
+}
+
+// GetJournaledMutations returns the number of mutations made by the current run,
+// recorded in the journal when it is enabled
+func GetJournaledMutations() int {
+	mutationJournal.mu.Lock()
+	defer mutationJournal.mu.Unlock()
+	return mutationJournal.mutations
+}
+
+// recordMutation appends a mutation to the journal. The mutation has already
+// happened, so a journal that cannot be written is logged and not returned.
+func recordMutation(action, resource string, before, after proto.Message) {
+	logger := clilog.GetLogger()
+
+	mutationJournal.mu.Lock()
+	defer mutationJournal.mu.Unlock()
+
+	mutationJournal.mutations++
+	if mutationJournal.path == "" {
+		return
+	}
+
+	entry := JournalEntry{
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		RunID:     mutationJournal.runID,
+		Action:    action,
+		Resource:  resource,
+	}
+	var err error
+	if before != nil {
+		if entry.Before, err = protojson.Marshal(before); err != nil {
+			logger.Warn("Failed to record mutation in journal", "resource", resource, "error", err)
+			return
+		}
+	}
+	if after != nil {
+		if entry.After, err = protojson.Marshal(after); err != nil {
+			logger.Warn("Failed to record mutation in journal", "resource", resource, "error", err)
+			return
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		logger.Warn("Failed to record mutation in journal", "resource", resource, "error", err)
+		return
+	}
+	f, err := os.OpenFile(mutationJournal.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		logger.Warn("Failed to open journal", "path", mutationJournal.path, "error", err)
+		return
+	}
+	defer f.Close()
+	if _, err = f.Write(append(data, '\n')); err != nil {
+		logger.Warn("Failed to record mutation in journal", "resource", resource, "error", err)
+	}
+}
+
+// ReadJournal reads the entries of a journal in the order they were recorded
+func ReadJournal(path string) ([]JournalEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	defer f.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry JournalEntry
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse journal line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	return entries, nil
+}
+
+// UndoRun reverses the applications, services and workloads created by a run,
+// in reverse order. Resources modified since, by a later entry of the journal
+// or outside of the tool, are left in place and reported as MODIFIED.
+// Deletions and updates are not reversed. The affected applications are saved
+// to the snapshot path before anything is deleted. With dryRun nothing is deleted.
+func UndoRun(path, runID, snapshot string, dryRun bool) ([]UndoResult, error) {
+	entries, err := ReadJournal(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var run []int
+	for i, entry := range entries {
+		if entry.RunID == runID {
+			run = append(run, i)
+		}
+	}
+	if len(run) == 0 {
+		return nil, fmt.Errorf("no mutations recorded for run %s in %s", runID, path)
+	}
+
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	if !dryRun {
+		appNames, err := getUndoApplications(apphubClient, entries, run)
+		if err != nil {
+			return nil, err
+		}
+		if len(appNames) > 0 {
+			managementProject := strings.Split(appNames[0], "/")[1]
+			if err = WriteSnapshot(managementProject, appNames, snapshot); err != nil {
+				return nil, fmt.Errorf("failed to write snapshot, nothing was undone: %w", err)
+			}
+		}
+	}
+
+	var results []UndoResult
+	undone := make(map[string]bool)
+	for i := len(run) - 1; i >= 0; i-- {
+		result := undoEntry(apphubClient, entries, run[i], undone, dryRun)
+		if result.Status == "UNDONE" {
+			undone[result.Resource] = true
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// getUndoApplications returns the existing applications that hold the
+// resources created by the entries of a run, to be saved before undo deletes them
+func getUndoApplications(apiclient appHubClient, entries []JournalEntry, run []int) ([]string, error) {
+	ctx := context.Background()
+	var appNames []string
+	for _, i := range run {
+		if !strings.HasPrefix(entries[i].Action, "CREATE_") {
+			continue
+		}
+		appName := getResourceApplication(entries[i].Resource)
+		if appName == "" || slices.Contains(appNames, appName) {
+			continue
+		}
+		if _, err := apiclient.GetApplication(ctx, &apphubpb.GetApplicationRequest{Name: appName}); err != nil {
+			if st, ok := status.FromError(err); ok && st.Code() == codes.NotFound {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get application %s: %w", appName, err)
+		}
+		appNames = append(appNames, appName)
+	}
+	return appNames, nil
+}
+
+// getResourceApplication returns the application of a resource name like
+// projects/p/locations/l/applications/a/services/s
+func getResourceApplication(resource string) string {
+	parts := strings.Split(resource, "/")
+	if len(parts) < 6 || parts[4] != "applications" {
+		return ""
+	}
+	return strings.Join(parts[:6], "/")
+}
+
+// undoEntry reverses the creation recorded by entries[i]. undone holds the
+// resources already reversed by the same undo.
+func undoEntry(apiclient appHubClient, entries []JournalEntry, i int, undone map[string]bool, dryRun bool) UndoResult {
+	entry := entries[i]
+	result := UndoResult{Action: entry.Action, Resource: entry.Resource}
+
+	if !strings.HasPrefix(entry.Action, "CREATE_") {
+		result.Status, result.Error = "SKIPPED", "only creations are reversed"
+		return result
+	}
+
+	current, recorded, err := getJournaledUpdateTime(apiclient, entry)
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.NotFound {
+			result.Status = "GONE"
+			return result
+		}
+		result.Status, result.Error = "FAILED", err.Error()
+		return result
+	}
+
+	for _, later := range entries[i+1:] {
+		if later.RunID != entry.RunID && (later.Resource == entry.Resource ||
+			strings.HasPrefix(later.Resource, entry.Resource+"/")) {
+			result.Status = "MODIFIED"
+			result.Error = fmt.Sprintf("changed by run %s at %s", later.RunID, later.Timestamp)
+			return result
+		}
+	}
+	if recorded != nil && !proto.Equal(current, recorded) {
+		result.Status, result.Error = "MODIFIED", "updated since it was created"
+		return result
+	}
+
+	if entry.Action == "CREATE_APPLICATION" {
+		registrations, err := listRegistrations(apiclient, &apphubpb.Application{Name: entry.Resource})
+		if err != nil {
+			result.Status, result.Error = "FAILED", err.Error()
+			return result
+		}
+		for _, r := range registrations {
+			if name := r.member().Name; !undone[name] {
+				result.Status, result.Error = "MODIFIED", fmt.Sprintf("%s was registered since", name)
+				return result
+			}
+		}
+	}
+
+	if dryRun {
+		result.Status = "UNDONE"
+		return result
+	}
+	if err = deleteJournaledResource(apiclient, entry); err != nil {
+		result.Status, result.Error = "FAILED", err.Error()
+		return result
+	}
+	result.Status = "UNDONE"
+	return result
+}
+
+// getJournaledUpdateTime returns the update time of a created resource and the
+// update time recorded in the journal, or nil if none was recorded
+func getJournaledUpdateTime(apiclient appHubClient, entry JournalEntry) (*timestamppb.Timestamp, *timestamppb.Timestamp, error) {
+	ctx := context.Background()
+	opts := protojson.UnmarshalOptions{DiscardUnknown: true}
+
+	switch entry.Action {
+	case "CREATE_APPLICATION":
+		app, err := apiclient.GetApplication(ctx, &apphubpb.GetApplicationRequest{Name: entry.Resource})
+		if err != nil {
+			return nil, nil, err
+		}
+		recorded := &apphubpb.Application{}
+		if err = opts.Unmarshal(entry.After, recorded); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse journal entry for %s: %w", entry.Resource, err)
+		}
+		return app.GetUpdateTime(), recorded.GetUpdateTime(), nil
+	case "CREATE_SERVICE":
+		service, err := apiclient.GetService(ctx, &apphubpb.GetServiceRequest{Name: entry.Resource})
+		if err != nil {
+			return nil, nil, err
+		}
+		recorded := &apphubpb.Service{}
+		if err = opts.Unmarshal(entry.After, recorded); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse journal entry for %s: %w", entry.Resource, err)
+		}
+		return service.GetUpdateTime(), recorded.GetUpdateTime(), nil
+	case "CREATE_WORKLOAD":
+		workload, err := apiclient.GetWorkload(ctx, &apphubpb.GetWorkloadRequest{Name: entry.Resource})
+		if err != nil {
+			return nil, nil, err
+		}
+		recorded := &apphubpb.Workload{}
+		if err = opts.Unmarshal(entry.After, recorded); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse journal entry for %s: %w", entry.Resource, err)
+		}
+		return workload.GetUpdateTime(), recorded.GetUpdateTime(), nil
+	}
+	return nil, nil, fmt.Errorf("unknown journal action %s", entry.Action)
+}
+
+// deleteJournaledResource deletes a resource created by a run
+func deleteJournaledResource(apiclient appHubClient, entry JournalEntry) error {
+	ctx := context.Background()
+	logger := clilog.GetLogger()
+
+	switch entry.Action {
+	case "CREATE_APPLICATION":
+		op, err := apiclient.DeleteApplication(ctx, &apphubpb.DeleteApplicationRequest{Name: entry.Resource})
+		if err != nil {
+			return fmt.Errorf("failed to start application deletion: %w", err)
+		}
+		if err = op.Wait(ctx); err != nil {
+			return fmt.Errorf("application deletion failed during wait: %w", err)
+		}
+		recordMutation("DELETE_APPLICATION", entry.Resource, nil, nil)
+	case "CREATE_SERVICE":
+		if err := deleteRegistration(apiclient, &registration{service: &apphubpb.Service{Name: entry.Resource}}); err != nil {
+			return err
+		}
+	case "CREATE_WORKLOAD":
+		if err := deleteRegistration(apiclient, &registration{workload: &apphubpb.Workload{Name: entry.Resource}}); err != nil {
+			return err
+		}
+	}
+	logger.Info("Creation reversed", "resource", entry.Resource)
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestRecordMutation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	defer SetJournal("", "")
+
+	app := &apphubpb.Application{Name: "projects/p/locations/us-west1/applications/shop", DisplayName: "shop"}
+
+	SetJournal(path, "run-1")
+	recordMutation("CREATE_APPLICATION", app.Name, nil, app)
+	recordMutation("DELETE_APPLICATION", app.Name, app, nil)
+	if got := GetJournaledMutations(); got != 2 {
+		t.Errorf("GetJournaledMutations() = %d, want 2", got)
+	}
+
+	SetJournal("", "run-2")
+	recordMutation("CREATE_APPLICATION", app.Name, nil, app)
+
+	entries, err := ReadJournal(path)
+	if err != nil {
+		t.Fatalf("ReadJournal() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("ReadJournal() = %d entries, want 2", len(entries))
+	}
+	if entries[0].RunID != "run-1" || entries[0].Action != "CREATE_APPLICATION" || entries[0].Resource != app.Name {
+		t.Errorf("ReadJournal() entry = %+v", entries[0])
+	}
+	if entries[0].Before != nil || entries[0].After == nil {
+		t.Errorf("ReadJournal() create entry before = %s, after = %s", entries[0].Before, entries[0].After)
+	}
+	if entries[1].Before == nil || entries[1].After != nil {
+		t.Errorf("ReadJournal() delete entry before = %s, after = %s", entries[1].Before, entries[1].After)
+	}
+}
+
+func TestUndoEntry(t *testing.T) {
+	serviceName := "projects/p/locations/us-west1/applications/shop/services/checkout"
+	created := timestamppb.New(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	createEntry := JournalEntry{
+		RunID:    "run-1",
+		Action:   "CREATE_SERVICE",
+		Resource: serviceName,
+		After:    []byte(`{"name":"` + serviceName + `","updateTime":"2025-01-01T12:00:00Z"}`),
+	}
+
+	tests := []struct {
+		name       string
+		entries    []JournalEntry
+		getService func(ctx context.Context, req *apphubpb.GetServiceRequest, opts ...gax.CallOption) (*apphubpb.Service, error)
+		wantStatus string
+	}{
+		{
+			name:    "Unchanged",
+			entries: []JournalEntry{createEntry},
+			getService: func(ctx context.Context, req *apphubpb.GetServiceRequest, opts ...gax.CallOption) (*apphubpb.Service, error) {
+				return &apphubpb.Service{Name: req.GetName(), UpdateTime: created}, nil
+			},
+			wantStatus: "UNDONE",
+		},
+		{
+			name:    "Updated since",
+			entries: []JournalEntry{createEntry},
+			getService: func(ctx context.Context, req *apphubpb.GetServiceRequest, opts ...gax.CallOption) (*apphubpb.Service, error) {
+				return &apphubpb.Service{Name: req.GetName(), UpdateTime: timestamppb.Now()}, nil
+			},
+			wantStatus: "MODIFIED",
+		},
+		{
+			name:    "Changed by a later run",
+			entries: []JournalEntry{createEntry, {RunID: "run-2", Action: "DELETE_SERVICE", Resource: serviceName}},
+			getService: func(ctx context.Context, req *apphubpb.GetServiceRequest, opts ...gax.CallOption) (*apphubpb.Service, error) {
+				return &apphubpb.Service{Name: req.GetName(), UpdateTime: created}, nil
+			},
+			wantStatus: "MODIFIED",
+		},
+		{
+			name:    "Deleted since",
+			entries: []JournalEntry{createEntry},
+			getService: func(ctx context.Context, req *apphubpb.GetServiceRequest, opts ...gax.CallOption) (*apphubpb.Service, error) {
+				return nil, status.Error(codes.NotFound, "not found")
+			},
+			wantStatus: "GONE",
+		},
+		{
+			name:       "Deletion",
+			entries:    []JournalEntry{{RunID: "run-1", Action: "DELETE_SERVICE", Resource: serviceName}},
+			wantStatus: "SKIPPED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &mockAppHubClient{getServiceFunc: tt.getService}
+			got := undoEntry(mockClient, tt.entries, 0, map[string]bool{}, true)
+			if got.Status != tt.wantStatus {
+				t.Errorf("undoEntry() status = %s, want %s (error %s)", got.Status, tt.wantStatus, got.Error)
+			}
+			if got.Resource != serviceName {
+				t.Errorf("undoEntry() resource = %s, want %s", got.Resource, serviceName)
+			}
+		})
+	}
+}
+
+func TestGetUndoApplications(t *testing.T) {
+	entries := []JournalEntry{
+		{RunID: "run-1", Action: "CREATE_APPLICATION", Resource: "projects/p/locations/global/applications/shop"},
+		{RunID: "run-1", Action: "CREATE_SERVICE", Resource: "projects/p/locations/global/applications/shop/services/checkout"},
+		{RunID: "run-1", Action: "CREATE_WORKLOAD", Resource: "projects/p/locations/us-west1/applications/gone/workloads/cart"},
+		{RunID: "run-1", Action: "DELETE_SERVICE", Resource: "projects/p/locations/us-west1/applications/old/services/web"},
+	}
+	mockClient := &mockAppHubClient{
+		getApplicationFunc: func(ctx context.Context, req *apphubpb.GetApplicationRequest, opts ...gax.CallOption) (*apphubpb.Application, error) {
+			if req.GetName() == "projects/p/locations/us-west1/applications/gone" {
+				return nil, status.Error(codes.NotFound, "not found")
+			}
+			return &apphubpb.Application{Name: req.GetName()}, nil
+		},
+	}
+
+	got, err := getUndoApplications(mockClient, entries, []int{0, 1, 2, 3})
+	if err != nil {
+		t.Fatalf("getUndoApplications() error = %v", err)
+	}
+	if len(got) != 1 || got[0] != "projects/p/locations/global/applications/shop" {
+		t.Errorf("getUndoApplications() = %v, want [projects/p/locations/global/applications/shop]", got)
+	}
+}
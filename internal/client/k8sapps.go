@@ -0,0 +1,155 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"internal/clilog"
+	"slices"
+
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// k8sServiceAssetTypes route traffic to workloads and often do not carry the app labels
+var k8sServiceAssetTypes = []string{"k8s.io/Service", "gateway.networking.k8s.io/Gateway"}
+
+// getK8sServiceAssetTypes returns the Kubernetes Service and Gateway asset types of a search
+func getK8sServiceAssetTypes(assetTypes []string) []string {
+	var serviceTypes []string
+	for _, assetType := range assetTypes {
+		if slices.Contains(k8sServiceAssetTypes, assetType) {
+			serviceTypes = append(serviceTypes, assetType)
+		}
+	}
+	return serviceTypes
+}
+
+// getK8sSelector returns the pod selector of a Kubernetes Service, read from
+// the resource data of the search result
+func getK8sSelector(asset *assetpb.ResourceSearchResult) map[string]string {
+	for _, versioned := range asset.GetVersionedResources() {
+		spec := versioned.GetResource().GetFields()["spec"].GetStructValue().GetFields()
+		if selector := getStringMap(spec["selector"].GetStructValue().GetFields()); len(selector) > 0 {
+			return selector
+		}
+	}
+	return nil
+}
+
+// getK8sPodLabels returns the labels of the pods of a workload, read from the
+// pod template in the resource data, or the labels of the workload itself
+func getK8sPodLabels(asset *assetpb.ResourceSearchResult) map[string]string {
+	for _, versioned := range asset.GetVersionedResources() {
+		spec := versioned.GetResource().GetFields()["spec"].GetStructValue().GetFields()
+		metadata := spec["template"].GetStructValue().GetFields()["metadata"].GetStructValue().GetFields()
+		if labels := getStringMap(metadata["labels"].GetStructValue().GetFields()); len(labels) > 0 {
+			return labels
+		}
+	}
+	return asset.GetLabels()
+}
+
+func getStringMap(fields map[string]*structpb.Value) map[string]string {
+	if len(fields) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(fields))
+	for key, value := range fields {
+		m[key] = value.GetStringValue()
+	}
+	return m
+}
+
+// selects returns true if every label of the selector matches the labels
+func selects(selector, labels map[string]string) bool {
+	for key, value := range selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return len(selector) > 0
+}
+
+// getK8sAppName returns the value of the first label key that is a valid application name
+func getK8sAppName(labels map[string]string, labelKeys []string) string {
+	for _, labelKey := range labelKeys {
+		if value := labels[labelKey]; value != "" && isValidAppName(value) {
+			return value
+		}
+	}
+	return ""
+}
+
+// groupKubernetesApps returns the assets to register and their application names.
+// Labeled assets are named by the label keys. A Service without the labels joins
+// the application of the workloads its selector matches. Other Services and
+// Gateways without the labels join the application of their namespace, when the
+// namespace has only one.
+func groupKubernetesApps(labeled, services []*assetpb.ResourceSearchResult, labelKeys []string,
+) ([]*assetpb.ResourceSearchResult, map[string]string) {
+	logger := clilog.GetLogger()
+	var assets []*assetpb.ResourceSearchResult
+	appNames := make(map[string]string)
+	namespaceApps := make(map[string][]string)
+	namespaceWorkloads := make(map[string][]*assetpb.ResourceSearchResult)
+
+	for _, asset := range labeled {
+		appName := getK8sAppName(asset.GetLabels(), labelKeys)
+		if appName == "" {
+			logger.Warn("Skipping asset, none of the labels is a valid application name", "assetName", asset.GetName())
+			continue
+		}
+		assets = append(assets, asset)
+		appNames[asset.GetName()] = appName
+		namespace := asset.GetParentFullResourceName()
+		if !slices.Contains(namespaceApps[namespace], appName) {
+			namespaceApps[namespace] = append(namespaceApps[namespace], appName)
+		}
+		if !slices.Contains(k8sServiceAssetTypes, asset.GetAssetType()) {
+			namespaceWorkloads[namespace] = append(namespaceWorkloads[namespace], asset)
+		}
+	}
+
+	for _, asset := range services {
+		if _, ok := appNames[asset.GetName()]; ok {
+			continue
+		}
+		apps := namespaceApps[asset.GetParentFullResourceName()]
+		if selector := getK8sSelector(asset); len(selector) > 0 {
+			var selected []string
+			for _, workload := range namespaceWorkloads[asset.GetParentFullResourceName()] {
+				if appName := appNames[workload.GetName()]; selects(selector, getK8sPodLabels(workload)) &&
+					!slices.Contains(selected, appName) {
+					selected = append(selected, appName)
+				}
+			}
+			if len(selected) > 0 {
+				apps = selected
+			}
+		}
+		switch len(apps) {
+		case 0:
+			logger.Info("Skipping asset, no application in its namespace", "assetName", asset.GetName())
+		case 1:
+			logger.Info("Adding asset to the application of its namespace", "assetName", asset.GetName(), "application", apps[0])
+			assets = append(assets, asset)
+			appNames[asset.GetName()] = apps[0]
+		default:
+			logger.Warn("Skipping asset, its namespace has more than one application", "assetName", asset.GetName(),
+				"applications", apps)
+		}
+	}
+	return assets, appNames
+}
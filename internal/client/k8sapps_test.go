@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestGroupKubernetesApps(t *testing.T) {
+	const (
+		shop    = "//container.googleapis.com/projects/p/locations/us-west1/clusters/c/k8s/namespaces/shop"
+		shared  = "//container.googleapis.com/projects/p/locations/us-west1/clusters/c/k8s/namespaces/shared"
+		unowned = "//container.googleapis.com/projects/p/locations/us-west1/clusters/c/k8s/namespaces/unowned"
+	)
+	k8sAsset := func(namespace, name string, labels map[string]string) *assetpb.ResourceSearchResult {
+		return &assetpb.ResourceSearchResult{Name: namespace + "/" + name, ParentFullResourceName: namespace, Labels: labels}
+	}
+	withSpec := func(asset *assetpb.ResourceSearchResult, spec map[string]interface{}) *assetpb.ResourceSearchResult {
+		resource, err := structpb.NewStruct(map[string]interface{}{"spec": spec})
+		if err != nil {
+			t.Fatalf("structpb.NewStruct() error = %v", err)
+		}
+		asset.VersionedResources = []*assetpb.VersionedResource{{Version: "v1", Resource: resource}}
+		return asset
+	}
+	podTemplate := func(labels map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"template": map[string]interface{}{"metadata": map[string]interface{}{"labels": labels}}}
+	}
+	selector := func(labels map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"selector": labels}
+	}
+	labeled := []*assetpb.ResourceSearchResult{
+		k8sAsset(shop, "apps/deployments/web", map[string]string{"app.kubernetes.io/instance": "storefront", "app": "web"}),
+		k8sAsset(shop, "apps/deployments/worker", map[string]string{"app": "storefront"}),
+		k8sAsset(shop, "services/web", map[string]string{"app": "storefront"}),
+		k8sAsset(shared, "apps/deployments/a", map[string]string{"app": "alpha"}),
+		withSpec(k8sAsset(shared, "apps/deployments/b", map[string]string{"app.kubernetes.io/instance": "Beta", "app": "beta"}),
+			podTemplate(map[string]interface{}{"app": "beta", "tier": "api"})),
+		k8sAsset(shared, "apps/deployments/c", map[string]string{"app": "Gamma"}),
+	}
+	services := []*assetpb.ResourceSearchResult{
+		k8sAsset(shop, "services/web", map[string]string{"app": "storefront"}),
+		k8sAsset(shop, "gateways/external", nil),
+		k8sAsset(shared, "services/mesh", nil),
+		withSpec(k8sAsset(shared, "services/alpha", nil), selector(map[string]interface{}{"app": "alpha"})),
+		withSpec(k8sAsset(shared, "services/beta-api", nil), selector(map[string]interface{}{"tier": "api"})),
+		withSpec(k8sAsset(shared, "services/orphan", nil), selector(map[string]interface{}{"app": "delta"})),
+		withSpec(k8sAsset(shop, "services/legacy", nil), selector(map[string]interface{}{"app": "legacy"})),
+		k8sAsset(unowned, "services/legacy", nil),
+	}
+
+	assets, appNames := groupKubernetesApps(labeled, services, []string{"app.kubernetes.io/instance", "app"})
+
+	want := map[string]string{
+		shop + "/apps/deployments/web":    "storefront",
+		shop + "/apps/deployments/worker": "storefront",
+		shop + "/services/web":            "storefront",
+		shop + "/gateways/external":       "storefront",
+		shop + "/services/legacy":         "storefront",
+		shared + "/apps/deployments/a":    "alpha",
+		shared + "/apps/deployments/b":    "beta",
+		shared + "/services/alpha":        "alpha",
+		shared + "/services/beta-api":     "beta",
+	}
+	if !reflect.DeepEqual(appNames, want) {
+		t.Errorf("groupKubernetesApps() names = %v, want %v", appNames, want)
+	}
+	if len(assets) != len(want) {
+		t.Errorf("groupKubernetesApps() returned %d assets, want %d", len(assets), len(want))
+	}
+}
+
+func TestGetK8sServiceAssetTypes(t *testing.T) {
+	got := getK8sServiceAssetTypes([]string{"apps.k8s.io/Deployment", "k8s.io/Service", "gateway.networking.k8s.io/Gateway"})
+	want := []string{"k8s.io/Service", "gateway.networking.k8s.io/Gateway"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getK8sServiceAssetTypes() = %v, want %v", got, want)
+	}
+}
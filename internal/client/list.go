@@ -0,0 +1,268 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"internal/clilog"
+	"regexp"
+	"slices"
+	"strings"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	"google.golang.org/api/iterator"
+)
+
+// ApplicationSummary is an App Hub application with its attributes and the
+// number of registered services and workloads
+type ApplicationSummary struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+	Location    string `json:"location"`
+	Scope       string `json:"scope"`
+	// Managed is true if the application was created or adopted by the tool
+	Managed bool `json:"managed"`
+	AttributeSummary
+	Services  int `json:"services"`
+	Workloads int `json:"workloads"`
+}
+
+// AttributeSummary is the criticality, environment and owners of an
+// application, service or workload
+type AttributeSummary struct {
+	Criticality     string   `json:"criticality,omitempty"`
+	Environment     string   `json:"environment,omitempty"`
+	DeveloperOwners []string `json:"developerOwners,omitempty"`
+	OperatorOwners  []string `json:"operatorOwners,omitempty"`
+	BusinessOwners  []string `json:"businessOwners,omitempty"`
+}
+
+// appFilterKeys are the attributes applications can be filtered on
+var appFilterKeys = []string{
+	"display-name", "scope", "criticality", "environment",
+	"developer-owner", "operator-owner", "business-owner",
+}
+
+// appFilter matches an attribute of an application. An empty value matches
+// applications where the attribute is not set.
+type appFilter struct {
+	key   string
+	value *regexp.Regexp
+}
+
+// ListApps returns the applications in the management project that match
+// any name pattern and every filter. Filters are of the form key=value, where the
+// value is a glob or a /regex/.
+func ListApps(managementProject string, locations, names, filters []string) ([]ApplicationSummary, error) {
+	ctx := context.Background()
+	logger := clilog.GetLogger()
+
+	namePatterns, err := compilePatterns(names)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name pattern: %w", err)
+	}
+	appFilters, err := parseAppFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	var summaries []ApplicationSummary
+	for _, location := range locations {
+		parent := fmt.Sprintf("projects/%s/locations/%s", managementProject, location)
+		listApplications := apphubClient.ListApplications(ctx, &apphubpb.ListApplicationsRequest{
+			Parent: parent,
+		})
+		for {
+			app, err := listApplications.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to list applications: %w", err)
+			}
+
+			summary := newApplicationSummary(app)
+			if len(namePatterns) > 0 && !matchAny(namePatterns, summary.ID) {
+				continue
+			}
+			if !matchAppFilters(appFilters, summary) {
+				continue
+			}
+
+			if summary.Services, summary.Workloads, err = countMembers(apphubClient, app.GetName()); err != nil {
+				return nil, err
+			}
+			summaries = append(summaries, summary)
+		}
+		logger.Info("Listed applications", "location", location)
+	}
+	return summaries, nil
+}
+
+func newApplicationSummary(app *apphubpb.Application) ApplicationSummary {
+	name := app.GetName()
+	return ApplicationSummary{
+		ID:               name[strings.LastIndex(name, "/")+1:],
+		Name:             name,
+		DisplayName:      app.GetDisplayName(),
+		Location:         getNameSegment(name, "locations"),
+		Scope:            app.GetScope().GetType().String(),
+		Managed:          isManagedApp(app),
+		AttributeSummary: newAttributeSummary(app.GetAttributes()),
+	}
+}
+
+func newAttributeSummary(attributes *apphubpb.Attributes) AttributeSummary {
+	summary := AttributeSummary{
+		DeveloperOwners: getOwnerEmails(attributes.GetDeveloperOwners()),
+		OperatorOwners:  getOwnerEmails(attributes.GetOperatorOwners()),
+		BusinessOwners:  getOwnerEmails(attributes.GetBusinessOwners()),
+	}
+	if c := attributes.GetCriticality(); c != nil && c.GetType() != apphubpb.Criticality_TYPE_UNSPECIFIED {
+		summary.Criticality = c.GetType().String()
+	}
+	if e := attributes.GetEnvironment(); e != nil && e.GetType() != apphubpb.Environment_TYPE_UNSPECIFIED {
+		summary.Environment = e.GetType().String()
+	}
+	return summary
+}
+
+func getOwnerEmails(owners []*apphubpb.ContactInfo) []string {
+	var emails []string
+	for _, owner := range owners {
+		emails = append(emails, owner.GetEmail())
+	}
+	return emails
+}
+
+// getNameSegment returns the value that follows a collection in a resource name
+func getNameSegment(name, collection string) string {
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		if part == collection && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// countMembers returns the number of services and workloads in an application
+func countMembers(apiclient appHubClient, appName string) (services, workloads int, err error) {
+	ctx := context.Background()
+
+	listServices := apiclient.ListServices(ctx, &apphubpb.ListServicesRequest{Parent: appName})
+	for {
+		_, err = listServices.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to list services: %w", err)
+		}
+		services++
+	}
+
+	listWorkloads := apiclient.ListWorkloads(ctx, &apphubpb.ListWorkloadsRequest{Parent: appName})
+	for {
+		_, err = listWorkloads.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to list workloads: %w", err)
+		}
+		workloads++
+	}
+	return services, workloads, nil
+}
+
+func parseAppFilters(filters []string) ([]appFilter, error) {
+	var appFilters []appFilter
+	for _, filter := range filters {
+		key, value, found := strings.Cut(filter, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid filter %s, must be of the format key=value", filter)
+		}
+		if !slices.Contains(appFilterKeys, key) {
+			return nil, fmt.Errorf("invalid filter key %s, must be one of %s", key, strings.Join(appFilterKeys, ", "))
+		}
+		f := appFilter{key: key}
+		if value != "" {
+			re, err := compilePattern(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter %s: %w", filter, err)
+			}
+			f.value = re
+		}
+		appFilters = append(appFilters, f)
+	}
+	return appFilters, nil
+}
+
+// matchAppFilters returns true if an application matches every filter
+func matchAppFilters(filters []appFilter, summary ApplicationSummary) bool {
+	for _, f := range filters {
+		values := getAppFilterValues(f.key, summary)
+		if f.value == nil {
+			if len(values) > 0 {
+				return false
+			}
+			continue
+		}
+		matched := false
+		for _, v := range values {
+			if f.value.MatchString(v) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func getAppFilterValues(key string, summary ApplicationSummary) []string {
+	var value string
+	switch key {
+	case "display-name":
+		value = summary.DisplayName
+	case "scope":
+		value = summary.Scope
+	case "criticality":
+		value = summary.Criticality
+	case "environment":
+		value = summary.Environment
+	case "developer-owner":
+		return summary.DeveloperOwners
+	case "operator-owner":
+		return summary.OperatorOwners
+	case "business-owner":
+		return summary.BusinessOwners
+	}
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
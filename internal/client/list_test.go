@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"testing"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+)
+
+func TestNewApplicationSummary(t *testing.T) {
+	app := &apphubpb.Application{
+		Name:        "projects/host/locations/us-west1/applications/payments",
+		DisplayName: "Payments",
+		Scope:       &apphubpb.Scope{Type: apphubpb.Scope_REGIONAL},
+		Attributes: &apphubpb.Attributes{
+			Criticality:    &apphubpb.Criticality{Type: apphubpb.Criticality_MISSION_CRITICAL},
+			OperatorOwners: []*apphubpb.ContactInfo{{Email: "sre@example.com"}},
+		},
+	}
+
+	got := newApplicationSummary(app)
+	if got.ID != "payments" || got.Location != "us-west1" || got.Scope != "REGIONAL" {
+		t.Errorf("newApplicationSummary() = %+v", got)
+	}
+	if got.Criticality != "MISSION_CRITICAL" || got.Environment != "" {
+		t.Errorf("newApplicationSummary() = %+v", got)
+	}
+	if len(got.OperatorOwners) != 1 || got.OperatorOwners[0] != "sre@example.com" {
+		t.Errorf("newApplicationSummary() = %+v", got)
+	}
+}
+
+func TestMatchAppFilters(t *testing.T) {
+	owned := ApplicationSummary{
+		ID: "payments",
+		AttributeSummary: AttributeSummary{
+			Criticality:    "MISSION_CRITICAL",
+			Environment:    "PRODUCTION",
+			OperatorOwners: []string{"sre@example.com"},
+		},
+	}
+	unowned := ApplicationSummary{
+		ID:               "checkout",
+		AttributeSummary: AttributeSummary{Criticality: "MISSION_CRITICAL"},
+	}
+
+	tests := []struct {
+		name    string
+		filters []string
+		summary ApplicationSummary
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:    "Mission critical without operator owner",
+			filters: []string{"criticality=MISSION_CRITICAL", "operator-owner="},
+			summary: unowned,
+			want:    true,
+		},
+		{
+			name:    "Mission critical with operator owner",
+			filters: []string{"criticality=MISSION_CRITICAL", "operator-owner="},
+			summary: owned,
+			want:    false,
+		},
+		{
+			name:    "Owner glob",
+			filters: []string{"operator-owner=*@example.com"},
+			summary: owned,
+			want:    true,
+		},
+		{
+			name:    "Environment regex",
+			filters: []string{"environment=/^(PRODUCTION|STAGING)$/"},
+			summary: owned,
+			want:    true,
+		},
+		{
+			name:    "Unset attribute does not match a value",
+			filters: []string{"environment=PRODUCTION"},
+			summary: unowned,
+			want:    false,
+		},
+		{
+			name:    "Invalid key",
+			filters: []string{"owner=foo"},
+			wantErr: true,
+		},
+		{
+			name:    "Missing value separator",
+			filters: []string{"criticality"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := parseAppFilters(tt.filters)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAppFilters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := matchAppFilters(filters, tt.summary); got != tt.want {
+				t.Errorf("matchAppFilters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
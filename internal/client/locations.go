@@ -0,0 +1,80 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"internal/clilog"
+	"slices"
+
+	"google.golang.org/api/iterator"
+	locationpb "google.golang.org/genproto/googleapis/cloud/location"
+)
+
+// ALL_LOCATIONS is passed as a location to operate over every App Hub
+// location available to the management project
+const ALL_LOCATIONS = "all"
+
+var listLocationsFunc = listLocations
+
+// ResolveLocations returns the locations as is, unless one of them is
+// ALL_LOCATIONS, in which case the App Hub locations available to the
+// management project are returned
+func ResolveLocations(managementProject string, locations []string) ([]string, error) {
+	if !slices.Contains(locations, ALL_LOCATIONS) {
+		return locations, nil
+	}
+
+	logger := clilog.GetLogger()
+
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	resolved, err := listLocationsFunc(apphubClient, managementProject)
+	if err != nil {
+		return nil, err
+	}
+	if len(resolved) == 0 {
+		return nil, fmt.Errorf("no App Hub locations found for project %s", managementProject)
+	}
+	logger.Info("Resolved locations", "locations", resolved)
+	return resolved, nil
+}
+
+// listLocations returns the App Hub locations available to a project
+func listLocations(apiclient appHubClient, projectID string) ([]string, error) {
+	ctx := context.Background()
+	var locations []string
+
+	listLocations := apiclient.ListLocations(ctx, &locationpb.ListLocationsRequest{
+		Name: fmt.Sprintf("projects/%s", projectID),
+	})
+	for {
+		location, err := listLocations.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list locations: %w", err)
+		}
+		locations = append(locations, location.GetLocationId())
+	}
+	slices.Sort(locations)
+	return locations, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolveLocations(t *testing.T) {
+	getAppHubClientFunc = func() (appHubClient, error) {
+		return &mockAppHubClient{}, nil
+	}
+	defer func() {
+		getAppHubClientFunc = getAppHubClient
+		listLocationsFunc = listLocations
+	}()
+
+	tests := []struct {
+		name      string
+		locations []string
+		available []string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "Explicit locations",
+			locations: []string{"us-west1", "global"},
+			want:      []string{"us-west1", "global"},
+		},
+		{
+			name:      "All locations",
+			locations: []string{"all"},
+			available: []string{"global", "us-east1", "us-west1"},
+			want:      []string{"global", "us-east1", "us-west1"},
+		},
+		{
+			name:      "All with explicit locations",
+			locations: []string{"us-west1", "all"},
+			available: []string{"global", "us-west1"},
+			want:      []string{"global", "us-west1"},
+		},
+		{
+			name:      "No locations available",
+			locations: []string{"all"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listLocationsFunc = func(apiclient appHubClient, projectID string) ([]string, error) {
+				if projectID != "my-project" {
+					t.Errorf("listLocations() projectID = %s, want my-project", projectID)
+				}
+				return tt.available, nil
+			}
+			got, err := ResolveLocations("my-project", tt.locations)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveLocations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveLocations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
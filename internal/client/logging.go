@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"internal/clilog"
+	"slices"
 	"strings"
 
 	"cloud.google.com/go/logging"
@@ -37,6 +38,13 @@ var INCLUDED_RESOURCE_TYPES = []string{
 	"gce_instance_group",
 }
 
+// RESOURCE_TYPE_ASSETS maps the log resource types to CAIS asset types
+var RESOURCE_TYPE_ASSETS = map[string]string{
+	"cloud_run_revision": "run.googleapis.com/Service",
+	"k8s_pod":            "apps.k8s.io/Deployment",
+	"gce_instance_group": "compute.googleapis.com/InstanceGroup",
+}
+
 const k8s_deployment = "AND labels.\"logging.gke.io/top_level_controller_type\"=\"Deployment\""
 
 func filterLogs(projectID, labelKey, labelValue string, locations []string) (map[string]logAsset, error) {
@@ -52,8 +60,13 @@ func filterLogs(projectID, labelKey, labelValue string, locations []string) (map
 	}
 	defer client.Close()
 
+	resourceTypeFilter := generateResourceTypeFilter()
+	if resourceTypeFilter == "" {
+		return nil, fmt.Errorf("none of the selected asset types can be discovered from logs")
+	}
+
 	filter := fmt.Sprintf("%s AND (labels.%s=\"%s\") AND %s", generateLocationFilter(locations),
-		labelKey, labelValue, generateResourceTypeFilter())
+		labelKey, labelValue, resourceTypeFilter)
 
 	logger.Info("Searching logs with query", "query", filter)
 
@@ -103,8 +116,13 @@ func generateLocationFilter(locations []string) string {
 func generateResourceTypeFilter() string {
 	var clauses []string
 
+	assetTypes := getSearchAssetTypes(getIncludedAssetTypes(), nil)
+
 	for _, rt := range INCLUDED_RESOURCE_TYPES {
 		var clause string
+		if !slices.Contains(assetTypes, RESOURCE_TYPE_ASSETS[rt]) {
+			continue
+		}
 		if rt == "k8s_pod" {
 			clause = fmt.Sprintf(`(resource.type="%s" AND %s)`, rt, k8s_deployment)
 		} else {
@@ -0,0 +1,332 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"internal/clilog"
+	"slices"
+	"strings"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	"google.golang.org/api/iterator"
+)
+
+// MovedMember is a service or workload moved between applications
+type MovedMember struct {
+	ApplicationMember
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// registration is a service or workload and the application that holds it
+type registration struct {
+	appName  string
+	managed  bool
+	service  *apphubpb.Service
+	workload *apphubpb.Workload
+}
+
+var reassignRegistrations bool
+
+// SetReassign allows generate to move a discovered service or workload that is
+// already registered in another application to the generated application
+func SetReassign(reassign bool) {
+	reassignRegistrations = reassign
+}
+
+func (r *registration) member() ApplicationMember {
+	if r.service != nil {
+		return newServiceMember(r.service)
+	}
+	return newWorkloadMember(r.workload)
+}
+
+// matches returns true if the registration is for one of the discovered names or resource URIs
+func (r *registration) matches(discoveredNames, uris []string) bool {
+	m := r.member()
+	return slices.Contains(discoveredNames, m.DiscoveredName) || slices.Contains(uris, m.URI)
+}
+
+// MoveMembers moves discovered services and workloads, selected by discovered
+// name or resource URI, from the application that currently holds them to the
+// named application. The registration keeps its ID, display name, description
+// and attributes. The moved members are returned; with dryRun nothing is moved.
+func MoveMembers(managementProject, name string, locations, discoveredNames, uris []string,
+	includeUnmanaged, dryRun bool,
+) ([]MovedMember, error) {
+	if len(discoveredNames) == 0 && len(uris) == 0 {
+		return nil, fmt.Errorf("at least one discovered name or uri is required")
+	}
+
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	target, err := findApplication(apphubClient, managementProject, name, locations)
+	if err != nil {
+		return nil, err
+	}
+	if !isManagedApp(target) && !includeUnmanaged {
+		return nil, errUnmanaged(target.GetName())
+	}
+
+	// generate registers regional resources in global applications
+	searchLocations := slices.Clone(locations)
+	if !slices.Contains(searchLocations, "global") {
+		searchLocations = append(searchLocations, "global")
+	}
+	registrations, err := findRegistrationsFunc(apphubClient, managementProject, searchLocations, discoveredNames, uris)
+	if err != nil {
+		return nil, err
+	}
+	if len(registrations) == 0 {
+		return nil, fmt.Errorf("no registered service or workload matches the discovered names or uris in %s",
+			strings.Join(searchLocations, ", "))
+	}
+
+	var pending []*registration
+	var moved []MovedMember
+	for _, r := range registrations {
+		if r.appName == target.GetName() {
+			continue
+		}
+		if !r.managed && !includeUnmanaged {
+			return nil, errUnmanaged(r.appName)
+		}
+		pending = append(pending, r)
+		moved = append(moved, MovedMember{ApplicationMember: r.member(), From: r.appName, To: target.GetName()})
+	}
+
+	if dryRun {
+		return moved, nil
+	}
+	for i, r := range pending {
+		if moved[i].Name, err = moveRegistration(apphubClient, r, target.GetName()); err != nil {
+			return moved[:i], err
+		}
+	}
+	return moved, nil
+}
+
+// findRegistrations returns the registrations of the discovered names and
+// resource URIs in every application of the locations
+func findRegistrations(apiclient appHubClient, projectID string, locations, discoveredNames, uris []string) ([]*registration, error) {
+	ctx := context.Background()
+	var registrations []*registration
+
+	for _, location := range locations {
+		listApplications := apiclient.ListApplications(ctx, &apphubpb.ListApplicationsRequest{
+			Parent: fmt.Sprintf("projects/%s/locations/%s", projectID, location),
+		})
+		for {
+			app, err := listApplications.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to list applications: %w", err)
+			}
+
+			appRegistrations, err := listRegistrations(apiclient, app)
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range appRegistrations {
+				if r.matches(discoveredNames, uris) {
+					registrations = append(registrations, r)
+				}
+			}
+		}
+	}
+	return registrations, nil
+}
+
+// listRegistrations returns the services and workloads registered in an application
+func listRegistrations(apiclient appHubClient, app *apphubpb.Application) ([]*registration, error) {
+	ctx := context.Background()
+	var registrations []*registration
+
+	listServices := apiclient.ListServices(ctx, &apphubpb.ListServicesRequest{Parent: app.GetName()})
+	for {
+		service, err := listServices.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list services: %w", err)
+		}
+		registrations = append(registrations, &registration{appName: app.GetName(), managed: isManagedApp(app), service: service})
+	}
+
+	listWorkloads := apiclient.ListWorkloads(ctx, &apphubpb.ListWorkloadsRequest{Parent: app.GetName()})
+	for {
+		workload, err := listWorkloads.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workloads: %w", err)
+		}
+		registrations = append(registrations, &registration{appName: app.GetName(), managed: isManagedApp(app), workload: workload})
+	}
+	return registrations, nil
+}
+
+// reassignRegistration moves a discovered service or workload that generate
+// could not register because another application in the location, or in the
+// global location, already holds it
+func reassignRegistration(apiclient appHubClient, projectID, location, appID, discoveredName string) error {
+	logger := clilog.GetLogger()
+
+	locations := []string{location}
+	if location != "global" {
+		locations = append(locations, "global")
+	}
+	registrations, err := findRegistrations(apiclient, projectID, locations, []string{discoveredName}, nil)
+	if err != nil {
+		return err
+	}
+	if len(registrations) == 0 {
+		logger.Warn("Discovered service or workload is registered outside of the management project. Skipping reassignment",
+			"discoveredName", discoveredName)
+		return nil
+	}
+
+	r := registrations[0]
+	target := fmt.Sprintf("projects/%s/locations/%s/applications/%s", projectID, location, appID)
+	if r.appName == target {
+		return nil
+	}
+	if !r.managed && !toolProvenance.includeUnmanaged {
+		return fmt.Errorf("%s is registered in application %s which was not created by apphub-app-creator",
+			discoveredName, r.appName)
+	}
+	_, err = moveRegistration(apiclient, r, target)
+	return err
+}
+
+// moveRegistration deletes a registration and registers it again in the
+// target application. If the second step fails, the original registration is
+// restored. The name of the new registration is returned.
+func moveRegistration(apiclient appHubClient, r *registration, target string) (string, error) {
+	logger := clilog.GetLogger()
+	m := r.member()
+
+	logger.Info("Moving registration", "member", m.Name, "from", r.appName, "to", target)
+
+	if err := deleteRegistration(apiclient, r); err != nil {
+		return "", err
+	}
+
+	name, err := createRegistration(apiclient, r, target)
+	if err == nil {
+		logger.Info("Registration successfully moved.", "member", name, "from", r.appName)
+		return name, nil
+	}
+
+	logger.Warn("Failed to register in the target application. Rolling back", "member", m.Name, "error", err)
+	if _, rollbackErr := createRegistration(apiclient, r, r.appName); rollbackErr != nil {
+		return "", fmt.Errorf("failed to move %s: %w, rollback failed: %v", m.Name, err, rollbackErr)
+	}
+	return "", fmt.Errorf("failed to move %s, the original registration was restored: %w", m.Name, err)
+}
+
+func deleteRegistration(apiclient appHubClient, r *registration) error {
+	ctx := context.Background()
+
+	if r.service != nil {
+		op, err := apiclient.DeleteService(ctx, &apphubpb.DeleteServiceRequest{Name: r.service.GetName()})
+		if err != nil {
+			return fmt.Errorf("failed to start service deletion for %s: %w", r.service.GetName(), err)
+		}
+		if err := op.Wait(ctx); err != nil {
+			return fmt.Errorf("wait for service deletion failed for %s: %w", r.service.GetName(), err)
+		}
+		recordMutation("DELETE_SERVICE", r.service.GetName(), r.service, nil)
+		return nil
+	}
+
+	op, err := apiclient.DeleteWorkload(ctx, &apphubpb.DeleteWorkloadRequest{Name: r.workload.GetName()})
+	if err != nil {
+		return fmt.Errorf("failed to start workload deletion for %s: %w", r.workload.GetName(), err)
+	}
+	if err := op.Wait(ctx); err != nil {
+		return fmt.Errorf("wait for workload deletion failed for %s: %w", r.workload.GetName(), err)
+	}
+	recordMutation("DELETE_WORKLOAD", r.workload.GetName(), r.workload, nil)
+	return nil
+}
+
+func createRegistration(apiclient appHubClient, r *registration, appName string) (string, error) {
+	ctx := context.Background()
+
+	if r.service != nil {
+		op, err := apiclient.CreateService(ctx, newCreateServiceRequest(r.service, appName))
+		if err != nil {
+			return "", fmt.Errorf("failed to start service registration: %w", err)
+		}
+		service, err := op.Wait(ctx)
+		if err != nil {
+			return "", fmt.Errorf("service registration failed during wait: %w", err)
+		}
+		recordMutation("CREATE_SERVICE", service.GetName(), nil, service)
+		return service.GetName(), nil
+	}
+
+	op, err := apiclient.CreateWorkload(ctx, newCreateWorkloadRequest(r.workload, appName))
+	if err != nil {
+		return "", fmt.Errorf("failed to start workload registration: %w", err)
+	}
+	workload, err := op.Wait(ctx)
+	if err != nil {
+		return "", fmt.Errorf("workload registration failed during wait: %w", err)
+	}
+	recordMutation("CREATE_WORKLOAD", workload.GetName(), nil, workload)
+	return workload.GetName(), nil
+}
+
+// newCreateServiceRequest registers the discovered service of an existing
+// service in an application, keeping its ID and user set fields
+func newCreateServiceRequest(service *apphubpb.Service, appName string) *apphubpb.CreateServiceRequest {
+	return &apphubpb.CreateServiceRequest{
+		Parent:    appName,
+		ServiceId: getAssetShortName(service.GetName()),
+		Service: &apphubpb.Service{
+			DiscoveredService: service.GetDiscoveredService(),
+			DisplayName:       service.GetDisplayName(),
+			Description:       service.GetDescription(),
+			Attributes:        service.GetAttributes(),
+		},
+	}
+}
+
+// newCreateWorkloadRequest registers the discovered workload of an existing
+// workload in an application, keeping its ID and user set fields
+func newCreateWorkloadRequest(workload *apphubpb.Workload, appName string) *apphubpb.CreateWorkloadRequest {
+	return &apphubpb.CreateWorkloadRequest{
+		Parent:     appName,
+		WorkloadId: getAssetShortName(workload.GetName()),
+		Workload: &apphubpb.Workload{
+			DiscoveredWorkload: workload.GetDiscoveredWorkload(),
+			DisplayName:        workload.GetDisplayName(),
+			Description:        workload.GetDescription(),
+			Attributes:         workload.GetAttributes(),
+		},
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	"github.com/googleapis/gax-go/v2"
+)
+
+func TestRegistrationMatches(t *testing.T) {
+	service := &registration{
+		appName: "projects/p/locations/us-west1/applications/shop",
+		service: &apphubpb.Service{
+			Name:              "projects/p/locations/us-west1/applications/shop/services/checkout",
+			DiscoveredService: "projects/p/locations/us-west1/discoveredServices/ds-1",
+			ServiceReference:  &apphubpb.ServiceReference{Uri: "//run.googleapis.com/projects/p/locations/us-west1/services/checkout"},
+		},
+	}
+	workload := &registration{
+		appName: "projects/p/locations/us-west1/applications/shop",
+		workload: &apphubpb.Workload{
+			Name:               "projects/p/locations/us-west1/applications/shop/workloads/batch",
+			DiscoveredWorkload: "projects/p/locations/us-west1/discoveredWorkloads/dw-1",
+		},
+	}
+
+	tests := []struct {
+		name            string
+		r               *registration
+		discoveredNames []string
+		uris            []string
+		want            bool
+	}{
+		{
+			name:            "Discovered service name",
+			r:               service,
+			discoveredNames: []string{"projects/p/locations/us-west1/discoveredServices/ds-1"},
+			want:            true,
+		},
+		{
+			name: "Resource URI",
+			r:    service,
+			uris: []string{"//run.googleapis.com/projects/p/locations/us-west1/services/checkout"},
+			want: true,
+		},
+		{
+			name:            "Discovered workload name",
+			r:               workload,
+			discoveredNames: []string{"projects/p/locations/us-west1/discoveredWorkloads/dw-1"},
+			want:            true,
+		},
+		{
+			name:            "No match",
+			r:               workload,
+			discoveredNames: []string{"projects/p/locations/us-west1/discoveredServices/ds-1"},
+			want:            false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.matches(tt.discoveredNames, tt.uris); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCreateServiceRequest(t *testing.T) {
+	attr := &apphubpb.Attributes{Criticality: &apphubpb.Criticality{Type: apphubpb.Criticality_HIGH}}
+	service := &apphubpb.Service{
+		Name:              "projects/p/locations/us-west1/applications/shop/services/checkout",
+		DiscoveredService: "projects/p/locations/us-west1/discoveredServices/ds-1",
+		DisplayName:       "checkout",
+		Description:       "checkout service",
+		Attributes:        attr,
+		State:             apphubpb.Service_ACTIVE,
+	}
+
+	req := newCreateServiceRequest(service, "projects/p/locations/us-west1/applications/payments")
+	if req.GetParent() != "projects/p/locations/us-west1/applications/payments" {
+		t.Errorf("Parent = %s", req.GetParent())
+	}
+	if req.GetServiceId() != "checkout" {
+		t.Errorf("ServiceId = %s, want checkout", req.GetServiceId())
+	}
+	got := req.GetService()
+	if got.GetName() != "" || got.GetState() != apphubpb.Service_STATE_UNSPECIFIED {
+		t.Errorf("Service = %v, output only fields must not be set", got)
+	}
+	if got.GetDiscoveredService() != service.GetDiscoveredService() || got.GetDisplayName() != "checkout" ||
+		got.GetDescription() != "checkout service" || got.GetAttributes() != attr {
+		t.Errorf("Service = %v, want the user set fields of %v", got, service)
+	}
+}
+
+func TestNewCreateWorkloadRequest(t *testing.T) {
+	workload := &apphubpb.Workload{
+		Name:               "projects/p/locations/us-west1/applications/shop/workloads/batch",
+		DiscoveredWorkload: "projects/p/locations/us-west1/discoveredWorkloads/dw-1",
+		DisplayName:        "batch",
+	}
+
+	req := newCreateWorkloadRequest(workload, "projects/p/locations/us-west1/applications/payments")
+	if req.GetWorkloadId() != "batch" {
+		t.Errorf("WorkloadId = %s, want batch", req.GetWorkloadId())
+	}
+	if req.GetWorkload().GetDiscoveredWorkload() != workload.GetDiscoveredWorkload() ||
+		req.GetWorkload().GetName() != "" {
+		t.Errorf("Workload = %v", req.GetWorkload())
+	}
+}
+
+func TestMoveMembersSearchesGlobal(t *testing.T) {
+	const (
+		target = "projects/p/locations/us-west1/applications/shop"
+		global = "projects/p/locations/global/applications/legacy"
+	)
+	mockClient := &mockAppHubClient{
+		getApplicationFunc: func(ctx context.Context, req *apphubpb.GetApplicationRequest, opts ...gax.CallOption) (*apphubpb.Application, error) {
+			return &apphubpb.Application{Name: req.GetName(), Description: MANAGED_MARKER}, nil
+		},
+	}
+	getAppHubClientFunc = func() (appHubClient, error) {
+		return mockClient, nil
+	}
+	var searched []string
+	findRegistrationsFunc = func(apiclient appHubClient, projectID string, locations, discoveredNames, uris []string) ([]*registration, error) {
+		searched = locations
+		if !slices.Contains(discoveredNames, "ds-1") {
+			return nil, nil
+		}
+		return []*registration{{
+			appName: global,
+			managed: true,
+			service: &apphubpb.Service{
+				Name:              global + "/services/checkout",
+				DiscoveredService: "ds-1",
+			},
+		}}, nil
+	}
+	defer func() {
+		getAppHubClientFunc = getAppHubClient
+		findRegistrationsFunc = findRegistrations
+	}()
+
+	moved, err := MoveMembers("p", "shop", []string{"us-west1"}, []string{"ds-1"}, nil, false, true)
+	if err != nil {
+		t.Fatalf("MoveMembers() error = %v", err)
+	}
+	if !slices.Equal(searched, []string{"us-west1", "global"}) {
+		t.Errorf("MoveMembers() searched %v, want us-west1 and global", searched)
+	}
+	if len(moved) != 1 || moved[0].From != global || moved[0].To != target {
+		t.Errorf("MoveMembers() = %+v, want the member moved from the global application", moved)
+	}
+
+	if _, err = MoveMembers("p", "shop", []string{"us-west1"}, []string{"ds-2"}, nil, false, true); err == nil ||
+		!strings.Contains(err.Error(), "no registered service or workload matches") {
+		t.Errorf("MoveMembers() error = %v, want an error when nothing matches", err)
+	}
+}
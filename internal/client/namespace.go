@@ -0,0 +1,258 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"internal/clilog"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+	container "google.golang.org/api/container/v1"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// Namespace grouping options for GenerateAppsPerNamespace
+const (
+	NAMESPACE_GROUP_CLUSTER   = "cluster"
+	NAMESPACE_GROUP_NAMESPACE = "namespace"
+	NAMESPACE_GROUP_PROJECT   = "namespace-project"
+	NAMESPACE_GROUP_FLEET     = "namespace-fleet"
+)
+
+// CLUSTERS_PREFIX starts the description line listing the clusters of a namespace application
+const CLUSTERS_PREFIX = "clusters: "
+
+// maxClustersDescription keeps the clusters line within the App Hub description limit
+const maxClustersDescription = 1024
+
+var namespaceGroups = []string{NAMESPACE_GROUP_CLUSTER, NAMESPACE_GROUP_NAMESPACE, NAMESPACE_GROUP_PROJECT, NAMESPACE_GROUP_FLEET}
+
+var getClusterFleetFunc = getClusterFleet
+
+// ValidateNamespaceGroup returns an error if groupBy is not a supported namespace grouping
+func ValidateNamespaceGroup(groupBy string) error {
+	if groupBy == "" || slices.Contains(namespaceGroups, groupBy) {
+		return nil
+	}
+	return fmt.Errorf("invalid namespace grouping %q, must be one of %s", groupBy, strings.Join(namespaceGroups, ", "))
+}
+
+// getNamespaceAppLocation returns the location of the namespace applications.
+// Only the cluster grouping keeps a namespace in one cluster; the other
+// groupings use global, whatever the number of clusters found, so that an
+// application does not change location when its namespace spreads.
+func getNamespaceAppLocation(groupBy, appLocation string) string {
+	if groupBy == "" || groupBy == NAMESPACE_GROUP_CLUSTER {
+		return appLocation
+	}
+	return "global"
+}
+
+// k8sNamespace identifies a namespace from the parent of a Kubernetes asset
+type k8sNamespace struct {
+	project   string
+	location  string
+	cluster   string
+	namespace string
+}
+
+var k8sNamespaceRegex = regexp.MustCompile(`^//container\.googleapis\.com/projects/([^/]+)/(?:locations|zones)/([^/]+)/clusters/([^/]+)/k8s/namespaces/([^/]+)$`)
+
+// parseK8sNamespace parses a name like
+// //container.googleapis.com/projects/p/locations/l/clusters/c/k8s/namespaces/ns
+func parseK8sNamespace(parent string) (k8sNamespace, error) {
+	m := k8sNamespaceRegex.FindStringSubmatch(parent)
+	if m == nil {
+		return k8sNamespace{}, fmt.Errorf("unable to parse namespace from %s", parent)
+	}
+	return k8sNamespace{project: m[1], location: m[2], cluster: m[3], namespace: m[4]}, nil
+}
+
+// clusterName returns the cluster in the form project/location/cluster
+func (n k8sNamespace) clusterName() string {
+	return strings.Join([]string{n.project, n.location, n.cluster}, "/")
+}
+
+// namespaceGrouper derives application names that ignore the cluster of a namespace
+type namespaceGrouper struct {
+	groupBy string
+	fleets  map[string]string
+}
+
+func newNamespaceGrouper(groupBy string) *namespaceGrouper {
+	return &namespaceGrouper{groupBy: groupBy, fleets: make(map[string]string)}
+}
+
+// appName returns the application name for the namespace
+func (g *namespaceGrouper) appName(n k8sNamespace) (string, error) {
+	switch g.groupBy {
+	case NAMESPACE_GROUP_PROJECT:
+		return joinNamespaceAppName(n.namespace, n.project), nil
+	case NAMESPACE_GROUP_FLEET:
+		fleet, err := g.fleet(n)
+		if err != nil {
+			return "", err
+		}
+		return joinNamespaceAppName(n.namespace, fleet), nil
+	default:
+		return n.namespace, nil
+	}
+}
+
+// fleet returns the fleet host project of the namespace's cluster. Clusters
+// that are not registered to a fleet are treated as their own project's fleet.
+func (g *namespaceGrouper) fleet(n k8sNamespace) (string, error) {
+	logger := clilog.GetLogger()
+	if fleet, ok := g.fleets[n.clusterName()]; ok {
+		return fleet, nil
+	}
+	fleet, err := getClusterFleetFunc(fmt.Sprintf("projects/%s/locations/%s/clusters/%s", n.project, n.location, n.cluster))
+	if err != nil {
+		return "", err
+	}
+	if fleet == "" {
+		logger.Warn("Cluster is not registered to a fleet, using its project", "cluster", n.clusterName())
+		fleet = n.project
+	}
+	// the fleet project may be a project number
+	fleet = strings.TrimPrefix(fleet, "projects/")
+	if _, err := fmt.Sscanf(fleet, "%d", new(int64)); err == nil {
+		fleet = getProjectIDFunc("projects/"+fleet, context.Background())
+	}
+	g.fleets[n.clusterName()] = fleet
+	return fleet, nil
+}
+
+// joinNamespaceAppName joins the namespace and a qualifier, hashing the
+// qualifier when the result is too long for an application id
+func joinNamespaceAppName(namespace, qualifier string) string {
+	name := namespace + "-" + qualifier
+	if len(name) > 63 {
+		name = namespace + "-" + createShortSHA(qualifier)
+	}
+	return name
+}
+
+// getClusterFleet returns the fleet host project of a GKE cluster
+func getClusterFleet(cluster string) (string, error) {
+	ctx := context.Background()
+
+	containerService, err := container.NewService(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to create container client: %w", err)
+	}
+	c, err := containerService.Projects.Locations.Clusters.Get(cluster).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to get cluster %s: %w", cluster, err)
+	}
+	if c.Fleet == nil {
+		return "", nil
+	}
+	return c.Fleet.Project, nil
+}
+
+// namespaceGroup is the set of assets and clusters of one namespace application
+type namespaceGroup struct {
+	assets   []*assetpb.ResourceSearchResult
+	clusters []string
+}
+
+// groupNamespaces groups the assets by application name
+func groupNamespaces(assets []*assetpb.ResourceSearchResult, grouper *namespaceGrouper) (map[string]*namespaceGroup, error) {
+	groups := make(map[string]*namespaceGroup)
+	for _, asset := range assets {
+		n, err := parseK8sNamespace(asset.GetParentFullResourceName())
+		if err != nil {
+			return nil, err
+		}
+		appName, err := grouper.appName(n)
+		if err != nil {
+			return nil, err
+		}
+		group, ok := groups[appName]
+		if !ok {
+			group = &namespaceGroup{}
+			groups[appName] = group
+		}
+		group.assets = append(group.assets, asset)
+		if !slices.Contains(group.clusters, n.clusterName()) {
+			group.clusters = append(group.clusters, n.clusterName())
+		}
+	}
+	for _, group := range groups {
+		sort.Strings(group.clusters)
+	}
+	return groups, nil
+}
+
+// getClustersDescription returns the description line listing the clusters
+func getClustersDescription(clusters []string) string {
+	line := CLUSTERS_PREFIX + strings.Join(clusters, ", ")
+	for i := len(clusters) - 1; len(line) > maxClustersDescription && i > 0; i-- {
+		line = CLUSTERS_PREFIX + strings.Join(clusters[:i], ", ") + fmt.Sprintf(" and %d more", len(clusters)-i)
+	}
+	return line
+}
+
+// setClustersDescription replaces the clusters line of a description
+func setClustersDescription(description string, clusters []string) string {
+	var lines []string
+	for _, line := range strings.Split(description, "\n") {
+		if line != "" && !strings.HasPrefix(line, CLUSTERS_PREFIX) {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(append(lines, getClustersDescription(clusters)), "\n")
+}
+
+// describeNamespaceClusters records the clusters of a namespace application in its description
+func describeNamespaceClusters(apiclient appHubClient, applicationName string, clusters []string) error {
+	ctx := context.Background()
+	logger := clilog.GetLogger()
+
+	app, err := apiclient.GetApplication(ctx, &apphubpb.GetApplicationRequest{Name: applicationName})
+	if err != nil {
+		return fmt.Errorf("failed to get application %s: %w", applicationName, err)
+	}
+	description := setClustersDescription(app.GetDescription(), clusters)
+	if description == app.GetDescription() {
+		return nil
+	}
+
+	updateMask, _ := fieldmaskpb.New(app, "description")
+	op, err := apiclient.UpdateApplication(ctx, &apphubpb.UpdateApplicationRequest{
+		UpdateMask: updateMask,
+		Application: &apphubpb.Application{
+			Name:        applicationName,
+			Description: description,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start application update for %s: %w", applicationName, err)
+	}
+	updated, err := op.Wait(ctx)
+	if err != nil {
+		return fmt.Errorf("application update failed during wait for %s: %w", applicationName, err)
+	}
+	recordMutation("UPDATE_APPLICATION", applicationName, app, updated)
+	logger.Info("Application clusters updated", "application", applicationName, "clusters", len(clusters))
+	return nil
+}
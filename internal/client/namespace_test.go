@@ -0,0 +1,167 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+)
+
+func TestGroupNamespaces(t *testing.T) {
+	namespaceAsset := func(project, location, cluster, namespace string) *assetpb.ResourceSearchResult {
+		return &assetpb.ResourceSearchResult{
+			Name: "//container.googleapis.com/projects/" + project + "/locations/" + location + "/clusters/" + cluster +
+				"/k8s/namespaces/" + namespace + "/apps/deployments/web",
+			ParentFullResourceName: "//container.googleapis.com/projects/" + project + "/locations/" + location +
+				"/clusters/" + cluster + "/k8s/namespaces/" + namespace,
+		}
+	}
+	assets := []*assetpb.ResourceSearchResult{
+		namespaceAsset("shop-prod", "us-west1", "west", "checkout"),
+		namespaceAsset("shop-prod", "us-east1", "east", "checkout"),
+		namespaceAsset("shop-dev", "us-west1", "dev", "checkout"),
+		namespaceAsset("shop-prod", "us-west1", "west", "cart"),
+	}
+
+	getClusterFleetFunc = func(cluster string) (string, error) {
+		if strings.HasPrefix(cluster, "projects/shop-dev/") {
+			return "", nil
+		}
+		return "projects/1234", nil
+	}
+	getProjectIDFunc = func(project string, ctx context.Context) string {
+		return "shop-fleet"
+	}
+	defer func() {
+		getClusterFleetFunc = getClusterFleet
+		getProjectIDFunc = getProjectID
+	}()
+
+	tests := []struct {
+		groupBy string
+		want    map[string][]string
+	}{
+		{
+			groupBy: NAMESPACE_GROUP_NAMESPACE,
+			want: map[string][]string{
+				"checkout": {"shop-dev/us-west1/dev", "shop-prod/us-east1/east", "shop-prod/us-west1/west"},
+				"cart":     {"shop-prod/us-west1/west"},
+			},
+		},
+		{
+			groupBy: NAMESPACE_GROUP_PROJECT,
+			want: map[string][]string{
+				"checkout-shop-prod": {"shop-prod/us-east1/east", "shop-prod/us-west1/west"},
+				"checkout-shop-dev":  {"shop-dev/us-west1/dev"},
+				"cart-shop-prod":     {"shop-prod/us-west1/west"},
+			},
+		},
+		{
+			groupBy: NAMESPACE_GROUP_FLEET,
+			want: map[string][]string{
+				"checkout-shop-fleet": {"shop-prod/us-east1/east", "shop-prod/us-west1/west"},
+				"checkout-shop-dev":   {"shop-dev/us-west1/dev"},
+				"cart-shop-fleet":     {"shop-prod/us-west1/west"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.groupBy, func(t *testing.T) {
+			groups, err := groupNamespaces(assets, newNamespaceGrouper(tt.groupBy))
+			if err != nil {
+				t.Fatalf("groupNamespaces() error = %v", err)
+			}
+			got := make(map[string][]string)
+			for appName, group := range groups {
+				got[appName] = group.clusters
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groupNamespaces() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetClustersDescription(t *testing.T) {
+	marker := MANAGED_MARKER + "; version: v1"
+	got := setClustersDescription(marker, []string{"p/us-west1/west", "p/us-east1/east"})
+	want := marker + "\nclusters: p/us-west1/west, p/us-east1/east"
+	if got != want {
+		t.Errorf("setClustersDescription() = %q, want %q", got, want)
+	}
+	if got = setClustersDescription(want, []string{"p/us-west1/west"}); got != marker+"\nclusters: p/us-west1/west" {
+		t.Errorf("setClustersDescription() did not replace the clusters line: %q", got)
+	}
+
+	clusters := make([]string, 100)
+	for i := range clusters {
+		clusters[i] = "my-project/us-central1/cluster-with-a-long-name"
+	}
+	line := getClustersDescription(clusters)
+	if len(line) > maxClustersDescription || !strings.HasSuffix(line, "more") {
+		t.Errorf("getClustersDescription() = %d characters, %q", len(line), line[len(line)-20:])
+	}
+}
+
+func TestValidateNamespaceGroup(t *testing.T) {
+	for _, groupBy := range []string{"", NAMESPACE_GROUP_CLUSTER, NAMESPACE_GROUP_FLEET} {
+		if err := ValidateNamespaceGroup(groupBy); err != nil {
+			t.Errorf("ValidateNamespaceGroup(%q) error = %v", groupBy, err)
+		}
+	}
+	if err := ValidateNamespaceGroup("fleet"); err == nil {
+		t.Errorf("ValidateNamespaceGroup(fleet) did not fail")
+	}
+}
+
+func TestNamespaceAppLocationAsNamespaceSpreads(t *testing.T) {
+	namespaceAsset := func(location, cluster string) *assetpb.ResourceSearchResult {
+		parent := "//container.googleapis.com/projects/shop/locations/" + location + "/clusters/" + cluster +
+			"/k8s/namespaces/checkout"
+		return &assetpb.ResourceSearchResult{Name: parent + "/apps/deployments/web", ParentFullResourceName: parent}
+	}
+	oneCluster := []*assetpb.ResourceSearchResult{namespaceAsset("us-west1", "west")}
+	twoClusters := append(oneCluster, namespaceAsset("us-west1", "west-2"))
+
+	for _, groupBy := range []string{NAMESPACE_GROUP_NAMESPACE, NAMESPACE_GROUP_PROJECT} {
+		var before, after string
+		for i, assets := range [][]*assetpb.ResourceSearchResult{oneCluster, twoClusters} {
+			groups, err := groupNamespaces(assets, newNamespaceGrouper(groupBy))
+			if err != nil {
+				t.Fatalf("groupNamespaces() error = %v", err)
+			}
+			for appName := range groups {
+				name := getNamespaceAppLocation(groupBy, "us-west1") + "/" + appName
+				if i == 0 {
+					before = name
+				} else {
+					after = name
+				}
+			}
+		}
+		if before != after || !strings.HasPrefix(before, "global/") {
+			t.Errorf("%s: application %s in one cluster became %s in two clusters, want the same global application",
+				groupBy, before, after)
+		}
+	}
+	if got := getNamespaceAppLocation(NAMESPACE_GROUP_CLUSTER, "us-west1"); got != "us-west1" {
+		t.Errorf("getNamespaceAppLocation(cluster) = %s, want us-west1", got)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"internal/clilog"
+	"slices"
+	"strings"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// MANAGED_MARKER is written to the description of every application the tool
+// creates or adopts. Applications without it are treated as hand-crafted.
+const MANAGED_MARKER = "managed-by: apphub-app-creator"
+
+// provenance records who created an application
+type provenance struct {
+	version          string
+	mode             string
+	includeUnmanaged bool
+	// unmanaged are the existing applications the run left unchanged
+	// because the tool does not manage them
+	unmanaged []string
+}
+
+var toolProvenance = provenance{version: "dev"}
+
+// SetProvenance sets the tool version and generate mode recorded on the
+// applications the tool creates, and starts a new run
+func SetProvenance(version, mode string) {
+	if version != "" {
+		toolProvenance.version = version
+	}
+	toolProvenance.mode = mode
+	toolProvenance.unmanaged = nil
+}
+
+// GetUnmanagedApplications returns the applications generate skipped in the
+// current run because the tool does not manage them
+func GetUnmanagedApplications() []string {
+	return toolProvenance.unmanaged
+}
+
+// skipUnmanaged records an application generate skipped because the tool
+// does not manage it
+func skipUnmanaged(appName string) {
+	if !slices.Contains(toolProvenance.unmanaged, appName) {
+		toolProvenance.unmanaged = append(toolProvenance.unmanaged, appName)
+	}
+}
+
+// SetIncludeUnmanaged allows generate to register resources in applications
+// the tool did not create
+func SetIncludeUnmanaged(includeUnmanaged bool) {
+	toolProvenance.includeUnmanaged = includeUnmanaged
+}
+
+// getProvenanceMarker returns the marker line for an application created by
+// the current generate mode and, when applicable, rule
+func getProvenanceMarker(rule string) string {
+	parts := []string{MANAGED_MARKER, "version: " + toolProvenance.version}
+	if toolProvenance.mode != "" {
+		parts = append(parts, "mode: "+toolProvenance.mode)
+	}
+	if rule != "" {
+		parts = append(parts, "rule: "+rule)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// unmanagedError is returned when the tool is asked to modify an application
+// it does not manage
+type unmanagedError struct {
+	appName string
+}
+
+func (e *unmanagedError) Error() string {
+	return fmt.Sprintf("application %s was not created by apphub-app-creator, "+
+		"adopt it with apps adopt or use --include-unmanaged", e.appName)
+}
+
+func errUnmanaged(appName string) error {
+	return &unmanagedError{appName: appName}
+}
+
+// isUnmanaged returns true if err was returned for an application the tool
+// does not manage
+func isUnmanaged(err error) bool {
+	var unmanaged *unmanagedError
+	return errors.As(err, &unmanaged)
+}
+
+// isManagedApp returns true if the application was created or adopted by the tool
+func isManagedApp(app *apphubpb.Application) bool {
+	return strings.Contains(app.GetDescription(), MANAGED_MARKER)
+}
+
+// AdoptApps stamps existing applications with the provenance marker so the
+// tool is allowed to modify and delete them. When name is empty, every
+// unmanaged application in the locations is adopted. The names of the
+// adopted applications are returned.
+func AdoptApps(managementProject, name string, locations []string, dryRun bool) ([]string, error) {
+	ctx := context.Background()
+	logger := clilog.GetLogger()
+
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	var apps []*apphubpb.Application
+	for _, location := range locations {
+		parent := fmt.Sprintf("projects/%s/locations/%s", managementProject, location)
+
+		if name != "" {
+			app, err := apphubClient.GetApplication(ctx, &apphubpb.GetApplicationRequest{
+				Name: fmt.Sprintf("%s/applications/%s", parent, name),
+			})
+			if err != nil {
+				if st, ok := status.FromError(err); ok && st.Code() == codes.NotFound {
+					continue
+				}
+				return nil, fmt.Errorf("failed to get application %s: %w", name, err)
+			}
+			apps = append(apps, app)
+			continue
+		}
+
+		listApplications := apphubClient.ListApplications(ctx, &apphubpb.ListApplicationsRequest{Parent: parent})
+		for {
+			app, err := listApplications.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to list applications: %w", err)
+			}
+			apps = append(apps, app)
+		}
+	}
+
+	if name != "" && len(apps) == 0 {
+		return nil, fmt.Errorf("application %s not found in locations %v", name, locations)
+	}
+
+	var adopted []string
+	for _, app := range apps {
+		if isManagedApp(app) {
+			logger.Info("Application is already managed", "application", app.GetName())
+			continue
+		}
+		if !dryRun {
+			if err = adoptApp(apphubClient, app); err != nil {
+				return adopted, err
+			}
+		}
+		adopted = append(adopted, app.GetName())
+	}
+	return adopted, nil
+}
+
+// adoptApp appends the provenance marker to the description of an application
+func adoptApp(apiclient appHubClient, app *apphubpb.Application) error {
+	ctx := context.Background()
+	logger := clilog.GetLogger()
+
+	updateMask, _ := fieldmaskpb.New(app, "description")
+	op, err := apiclient.UpdateApplication(ctx, &apphubpb.UpdateApplicationRequest{
+		UpdateMask: updateMask,
+		Application: &apphubpb.Application{
+			Name:        app.GetName(),
+			Description: addProvenanceMarker(app.GetDescription(), getProvenanceMarker("")),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start application update for %s: %w", app.GetName(), err)
+	}
+	updated, err := op.Wait(ctx)
+	if err != nil {
+		return fmt.Errorf("application update failed during wait for %s: %w", app.GetName(), err)
+	}
+	recordMutation("UPDATE_APPLICATION", app.GetName(), app, updated)
+	logger.Info("Application adopted", "application", app.GetName())
+	return nil
+}
+
+// addProvenanceMarker appends the marker to an existing description
+func addProvenanceMarker(description, marker string) string {
+	if description == "" {
+		return marker
+	}
+	return description + "\n" + marker
+}
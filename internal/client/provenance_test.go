@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+	"github.com/googleapis/gax-go/v2"
+)
+
+func TestGetProvenanceMarker(t *testing.T) {
+	defer func() { toolProvenance = provenance{version: "dev"} }()
+
+	SetProvenance("v1.2.3", "rules")
+	want := "managed-by: apphub-app-creator; version: v1.2.3; mode: rules; rule: payments"
+	if got := getProvenanceMarker("payments"); got != want {
+		t.Errorf("getProvenanceMarker() = %v, want %v", got, want)
+	}
+
+	SetProvenance("", "label")
+	want = "managed-by: apphub-app-creator; version: v1.2.3; mode: label"
+	if got := getProvenanceMarker(""); got != want {
+		t.Errorf("getProvenanceMarker() = %v, want %v", got, want)
+	}
+}
+
+func TestIsManagedApp(t *testing.T) {
+	if isManagedApp(&apphubpb.Application{Description: "Payments team application"}) {
+		t.Errorf("isManagedApp() = true for an application without the marker")
+	}
+	adopted := addProvenanceMarker("Payments team application", getProvenanceMarker(""))
+	if !isManagedApp(&apphubpb.Application{Description: adopted}) {
+		t.Errorf("isManagedApp() = false for an adopted application")
+	}
+}
+
+func TestGetOrCreateAppHubApplicationUnmanaged(t *testing.T) {
+	defer func() { toolProvenance = provenance{version: "dev"} }()
+
+	mockClient := &mockAppHubClient{
+		getApplicationFunc: func(ctx context.Context, req *apphubpb.GetApplicationRequest, opts ...gax.CallOption) (*apphubpb.Application, error) {
+			return &apphubpb.Application{Name: req.GetName(), Description: "hand crafted"}, nil
+		},
+	}
+
+	if _, err := getOrCreateAppHubApplication(mockClient, "host", "us-west1", "shop", nil, ""); !isUnmanaged(err) {
+		t.Errorf("getOrCreateAppHubApplication() error = %v, want an unmanaged application error", err)
+	}
+
+	SetIncludeUnmanaged(true)
+	if _, err := getOrCreateAppHubApplication(mockClient, "host", "us-west1", "shop", nil, ""); err != nil {
+		t.Errorf("getOrCreateAppHubApplication() error = %v with unmanaged applications included", err)
+	}
+}
+
+func TestProcessAssetsSkipsUnmanagedApplications(t *testing.T) {
+	defer func() { toolProvenance = provenance{version: "dev"} }()
+	SetProvenance("v1", "label")
+
+	mockClient := &mockAppHubClient{
+		getApplicationFunc: func(ctx context.Context, req *apphubpb.GetApplicationRequest, opts ...gax.CallOption) (*apphubpb.Application, error) {
+			return &apphubpb.Application{Name: req.GetName(), Description: "hand crafted"}, nil
+		},
+		lookupDiscoveredServiceFunc: func(ctx context.Context, req *apphubpb.LookupDiscoveredServiceRequest, opts ...gax.CallOption) (*apphubpb.LookupDiscoveredServiceResponse, error) {
+			return &apphubpb.LookupDiscoveredServiceResponse{
+				DiscoveredService: &apphubpb.DiscoveredService{Name: "projects/host/locations/us-west1/discoveredServices/" + req.GetUri()},
+			}, nil
+		},
+	}
+	assets := []*assetpb.ResourceSearchResult{
+		{Name: "//run.googleapis.com/projects/p/locations/us-west1/services/checkout", AssetType: "run.googleapis.com/Service", Location: "us-west1", Labels: map[string]string{"app": "shop"}},
+		{Name: "//run.googleapis.com/projects/p/locations/us-west1/services/cart", AssetType: "run.googleapis.com/Service", Location: "us-west1", Labels: map[string]string{"app": "shop"}},
+		{Name: "//run.googleapis.com/projects/p/locations/us-west1/services/ledger", AssetType: "run.googleapis.com/Service", Location: "us-west1", Labels: map[string]string{"app": "billing"}},
+	}
+
+	generated, err := processAssets(assets, mockClient, "host", "us-west1", nil, false, "", nil,
+		func(asset *assetpb.ResourceSearchResult) string {
+			return asset.GetLabels()["app"]
+		})
+	if err != nil {
+		t.Fatalf("processAssets() error = %v, want the unmanaged applications to be skipped", err)
+	}
+	if len(generated) != 2 {
+		t.Errorf("processAssets() = %v, want both applications", generated)
+	}
+	if got, want := GetUnmanagedApplications(), []string{"shop", "billing"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetUnmanagedApplications() = %v, want %v", got, want)
+	}
+	for _, app := range NewGeneratedApplications(generated) {
+		if app.Action != "UNMANAGED" {
+			t.Errorf("NewGeneratedApplications() action of %s = %q, want UNMANAGED", app.Name, app.Action)
+		}
+	}
+}
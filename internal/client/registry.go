@@ -0,0 +1,310 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+	"gopkg.in/yaml.v3"
+)
+
+// assetTypeInfo describes how a CAIS asset type is registered in App Hub
+type assetTypeInfo struct {
+	// AssetType is the CAIS asset type
+	AssetType string `yaml:"assetType"`
+	// AppHubType is either discoveredService or discoveredWorkload
+	AppHubType string `yaml:"appHubType"`
+	// URI is a template that maps the CAIS resource name to the URI used to
+	// lookup the discovered service or workload. When empty, the CAIS resource
+	// name is used as is. The fields .URI, .Name, .Location, .Project and
+	// .ProjectNumber and the functions lower and replace are available.
+	URI string `yaml:"uri,omitempty"`
+	// FallbackLocations are tried, in order, when the lookup in the asset's
+	// location returns not found
+	FallbackLocations []string `yaml:"fallbackLocations,omitempty"`
+	// LocationAliases maps CAIS locations that are not App Hub regions
+	LocationAliases map[string]string `yaml:"locationAliases,omitempty"`
+	// Enabled asset types are searched by default
+	Enabled bool `yaml:"enabled"`
+	// Kubernetes asset types are searched by the Kubernetes generate modes
+	Kubernetes bool `yaml:"kubernetes,omitempty"`
+
+	uriTemplate *template.Template
+}
+
+// uriTemplateData is the data made available to the URI template
+type uriTemplateData struct {
+	URI           string
+	Name          string
+	Location      string
+	Project       string
+	ProjectNumber string
+}
+
+var uriTemplateFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"replace": func(old, new, s string) string {
+		return strings.ReplaceAll(s, old, new)
+	},
+}
+
+var assetTypeRegistry = newAssetTypeRegistry()
+
+func newAssetTypeRegistry() []*assetTypeInfo {
+	registry := []*assetTypeInfo{
+		// runtimes
+		{AssetType: "run.googleapis.com/Service", AppHubType: "discoveredService", Enabled: true},
+		{AssetType: "run.googleapis.com/Job", AppHubType: "discoveredWorkload", Enabled: true},
+		{AssetType: "apps.k8s.io/Deployment", AppHubType: "discoveredWorkload", Enabled: true, Kubernetes: true},
+		{AssetType: "apps.k8s.io/DaemonSet", AppHubType: "discoveredWorkload", Enabled: true, Kubernetes: true},
+		{AssetType: "apps.k8s.io/StatefulSet", AppHubType: "discoveredWorkload", Enabled: true, Kubernetes: true},
+		{AssetType: "compute.googleapis.com/InstanceGroup", AppHubType: "discoveredWorkload", Enabled: true},
+		{AssetType: "aiplatform.googleapis.com/ReasoningEngine", AppHubType: "discoveredWorkload", Enabled: true},
+		// 2nd gen functions are discovered through the Cloud Run service that backs them
+		{
+			AssetType: "cloudfunctions.googleapis.com/Function", AppHubType: "discoveredService", Enabled: true,
+			URI: "//run.googleapis.com/projects/{{ .Project }}/locations/{{ .Location }}/services/{{ .Name | lower | replace \"_\" \"-\" }}",
+		},
+		{AssetType: "cloudfunctions.googleapis.com/CloudFunction", AppHubType: "discoveredService", Enabled: true},
+		{
+			AssetType: "appengine.googleapis.com/Service", AppHubType: "discoveredService", Enabled: true,
+			LocationAliases: map[string]string{"us-central": "us-central1", "europe-west": "europe-west1"},
+		},
+		{AssetType: "aiplatform.googleapis.com/Endpoint", AppHubType: "discoveredService", Enabled: true},
+		// networking
+		{AssetType: "compute.googleapis.com/ForwardingRule", AppHubType: "discoveredService", Enabled: true},
+		{AssetType: "compute.googleapis.com/BackendService", AppHubType: "discoveredService", Enabled: true},
+		{AssetType: "k8s.io/Service", AppHubType: "discoveredService", Kubernetes: true},
+		{
+			AssetType: "gateway.networking.k8s.io/Gateway", AppHubType: "discoveredService", Kubernetes: true,
+			FallbackLocations: []string{"global"},
+		},
+		// storage
+		{AssetType: "storage.googleapis.com/Bucket", AppHubType: "discoveredService", Enabled: true},
+		{AssetType: "pubsub.googleapis.com/Topic", AppHubType: "discoveredService", Enabled: true},
+		{AssetType: "pubsub.googleapis.com/Subscription", AppHubType: "discoveredService", Enabled: true},
+		{AssetType: "cloudtasks.googleapis.com/Queue", AppHubType: "discoveredService", Enabled: true},
+		{AssetType: "file.googleapis.com/Instance", AppHubType: "discoveredService", Enabled: true},
+		// databases
+		{AssetType: "alloydb.googleapis.com/Instance", AppHubType: "discoveredService", Enabled: true},
+		{AssetType: "spanner.googleapis.com/Instance", AppHubType: "discoveredService", Enabled: true},
+		{
+			AssetType: "sqladmin.googleapis.com/Instance", AppHubType: "discoveredService", Enabled: true,
+			URI: "//sqladmin.googleapis.com/projects/{{ .ProjectNumber }}/instances/{{ .Name }}",
+		},
+		{AssetType: "redis.googleapis.com/Instance", AppHubType: "discoveredService", Enabled: true},
+		{AssetType: "redis.googleapis.com/Cluster", AppHubType: "discoveredService", Enabled: true},
+		{AssetType: "memcache.googleapis.com/Instance", AppHubType: "discoveredService", Enabled: true},
+		{AssetType: "memorystore.googleapis.com/Instance", AppHubType: "discoveredService", Enabled: true},
+		{AssetType: "bigtableadmin.googleapis.com/Instance", AppHubType: "discoveredService", Enabled: true},
+		{AssetType: "bigquery.googleapis.com/Dataset", AppHubType: "discoveredService", Enabled: true},
+		{AssetType: "firestore.googleapis.com/Database", AppHubType: "discoveredService", Enabled: true},
+		// config
+		{AssetType: "secretmanager.googleapis.com/Secret", AppHubType: "discoveredService", Enabled: true},
+	}
+	for _, info := range registry {
+		_ = info.compile()
+	}
+	return registry
+}
+
+func (info *assetTypeInfo) compile() (err error) {
+	if info.AssetType == "" {
+		return fmt.Errorf("assetType is a required field")
+	}
+	if info.AppHubType != "discoveredService" && info.AppHubType != "discoveredWorkload" {
+		return fmt.Errorf("invalid appHubType %s for %s, must be discoveredService or discoveredWorkload",
+			info.AppHubType, info.AssetType)
+	}
+	info.uriTemplate = nil
+	if info.URI != "" {
+		if info.uriTemplate, err = template.New(info.AssetType).Funcs(uriTemplateFuncs).Parse(info.URI); err != nil {
+			return fmt.Errorf("invalid uri template for %s: %w", info.AssetType, err)
+		}
+	}
+	return nil
+}
+
+// SetAssetTypeRegistry extends or overrides the built in asset type registry
+// with the assetTypes section of the config file. Entries for an asset type
+// that is already known replace the built in entry.
+func SetAssetTypeRegistry(configData []byte) error {
+	registry := newAssetTypeRegistry()
+
+	if len(configData) > 0 {
+		c := struct {
+			AssetTypes []*assetTypeInfo `yaml:"assetTypes,omitempty"`
+		}{}
+		if err := yaml.Unmarshal(configData, &c); err != nil {
+			return fmt.Errorf("failed to parse config: %w", err)
+		}
+		for _, info := range c.AssetTypes {
+			if err := info.compile(); err != nil {
+				return err
+			}
+			if i := findAssetTypeIndex(registry, info.AssetType); i >= 0 {
+				registry[i] = info
+			} else {
+				registry = append(registry, info)
+			}
+		}
+	}
+
+	assetTypeRegistry = registry
+	return nil
+}
+
+func findAssetTypeIndex(registry []*assetTypeInfo, assetType string) int {
+	for i, info := range registry {
+		if info.AssetType == assetType {
+			return i
+		}
+	}
+	return -1
+}
+
+// getAssetTypeInfo returns the registry entry of an asset type or nil
+func getAssetTypeInfo(assetType string) *assetTypeInfo {
+	if i := findAssetTypeIndex(assetTypeRegistry, assetType); i >= 0 {
+		return assetTypeRegistry[i]
+	}
+	return nil
+}
+
+// getIncludedAssetTypes returns the asset types searched by default
+func getIncludedAssetTypes() []string {
+	var assetTypes []string
+	for _, info := range assetTypeRegistry {
+		if info.Enabled {
+			assetTypes = append(assetTypes, info.AssetType)
+		}
+	}
+	return assetTypes
+}
+
+// getKubernetesAssetTypes returns the asset types searched by the Kubernetes modes
+func getKubernetesAssetTypes() []string {
+	var assetTypes []string
+	for _, info := range assetTypeRegistry {
+		if info.Kubernetes {
+			assetTypes = append(assetTypes, info.AssetType)
+		}
+	}
+	return assetTypes
+}
+
+// getSupportedAssetTypes returns the asset types App Hub can register
+func getSupportedAssetTypes() []string {
+	var assetTypes []string
+	for _, info := range assetTypeRegistry {
+		assetTypes = append(assetTypes, info.AssetType)
+	}
+	return assetTypes
+}
+
+// identifyServiceOrWorkload returns the App Hub type of an asset type.
+// Unknown asset types are treated as services.
+func identifyServiceOrWorkload(assetType string) string {
+	if info := getAssetTypeInfo(assetType); info != nil {
+		return info.AppHubType
+	}
+	return "discoveredService"
+}
+
+// fixResourceURI maps the CAIS resource name of an asset to the URI App Hub
+// uses to lookup the discovered service or workload.
+func fixResourceURI(resourceURI string, asset *assetpb.ResourceSearchResult) string {
+	if asset == nil {
+		return resourceURI
+	}
+	info := getAssetTypeInfo(asset.GetAssetType())
+	if info == nil || info.uriTemplate == nil {
+		return resourceURI
+	}
+
+	data := uriTemplateData{
+		URI:           resourceURI,
+		Name:          resourceURI[strings.LastIndex(resourceURI, "/")+1:],
+		Location:      asset.GetLocation(),
+		Project:       getURIProject(resourceURI),
+		ProjectNumber: strings.TrimPrefix(asset.GetProject(), "projects/"),
+	}
+
+	var buf bytes.Buffer
+	if err := info.uriTemplate.Execute(&buf, data); err != nil {
+		return resourceURI
+	}
+	return buf.String()
+}
+
+// getLookupLocations returns the locations in which a discovered service or
+// workload is looked up, in order. Without an asset, as for resources found in
+// Cloud Logging, the asset type is derived from the resource URI.
+func getLookupLocations(location, resourceURI string, asset *assetpb.ResourceSearchResult) []string {
+	lookupLocations := []string{location}
+	var info *assetTypeInfo
+	if asset != nil {
+		info = getAssetTypeInfo(asset.GetAssetType())
+	} else {
+		info = getAssetTypeInfoForURI(resourceURI)
+	}
+	if info != nil {
+		for _, l := range info.FallbackLocations {
+			if l != location {
+				lookupLocations = append(lookupLocations, l)
+			}
+		}
+	}
+	return lookupLocations
+}
+
+// getAssetTypeInfoForURI returns the asset type with fallback locations whose
+// API group is a segment of the resource URI, such as gateway.networking.k8s.io
+func getAssetTypeInfoForURI(resourceURI string) *assetTypeInfo {
+	for _, info := range assetTypeRegistry {
+		group, _, _ := strings.Cut(info.AssetType, "/")
+		if len(info.FallbackLocations) > 0 && strings.Contains(resourceURI, "/"+group+"/") {
+			return info
+		}
+	}
+	return nil
+}
+
+// getAssetLocation returns the location of an asset, translated through the
+// location aliases of its asset type
+func getAssetLocation(asset *assetpb.ResourceSearchResult) string {
+	location := strings.ToLower(asset.GetLocation())
+	if info := getAssetTypeInfo(asset.GetAssetType()); info != nil {
+		if alias, ok := info.LocationAliases[location]; ok {
+			return alias
+		}
+	}
+	return location
+}
+
+// getURIProject returns the project segment of a resource URI
+func getURIProject(resourceURI string) string {
+	parts := strings.Split(resourceURI, "/")
+	for i, part := range parts {
+		if (part == "projects" || part == "apps") && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
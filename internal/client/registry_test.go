@@ -0,0 +1,168 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"slices"
+	"testing"
+
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+)
+
+func TestFixResourceURI(t *testing.T) {
+	tests := []struct {
+		name  string
+		uri   string
+		asset *assetpb.ResourceSearchResult
+		want  string
+	}{
+		{
+			name: "Cloud SQL uses sqladmin and the project number",
+			uri:  "//cloudsql.googleapis.com/projects/my-project/instances/pay-db",
+			asset: &assetpb.ResourceSearchResult{
+				AssetType: "sqladmin.googleapis.com/Instance",
+				Project:   "projects/12345",
+			},
+			want: "//sqladmin.googleapis.com/projects/12345/instances/pay-db",
+		},
+		{
+			name: "2nd gen function maps to its Cloud Run service",
+			uri:  "//cloudfunctions.googleapis.com/projects/my-project/locations/us-west1/functions/Process_Order",
+			asset: &assetpb.ResourceSearchResult{
+				AssetType: "cloudfunctions.googleapis.com/Function",
+				Project:   "projects/12345",
+				Location:  "us-west1",
+			},
+			want: "//run.googleapis.com/projects/my-project/locations/us-west1/services/process-order",
+		},
+		{
+			name: "Function in another region keeps its region in the Cloud Run URI",
+			uri:  "//cloudfunctions.googleapis.com/projects/my-project/locations/europe-west1/functions/resize_image",
+			asset: &assetpb.ResourceSearchResult{
+				AssetType: "cloudfunctions.googleapis.com/Function",
+				Project:   "projects/12345",
+				Location:  "europe-west1",
+			},
+			want: "//run.googleapis.com/projects/my-project/locations/europe-west1/services/resize-image",
+		},
+		{
+			name:  "No asset",
+			uri:   "//run.googleapis.com/projects/my-project/locations/us-west1/services/checkout",
+			asset: nil,
+			want:  "//run.googleapis.com/projects/my-project/locations/us-west1/services/checkout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fixResourceURI(tt.uri, tt.asset); got != tt.want {
+				t.Errorf("fixResourceURI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAssetLocation(t *testing.T) {
+	tests := []struct {
+		name  string
+		asset *assetpb.ResourceSearchResult
+		want  string
+	}{
+		{
+			name:  "App Engine legacy region",
+			asset: &assetpb.ResourceSearchResult{AssetType: "appengine.googleapis.com/Service", Location: "us-central"},
+			want:  "us-central1",
+		},
+		{
+			name:  "BigQuery multi-region",
+			asset: &assetpb.ResourceSearchResult{AssetType: "bigquery.googleapis.com/Dataset", Location: "US"},
+			want:  "us",
+		},
+		{
+			name:  "App Engine legacy European region",
+			asset: &assetpb.ResourceSearchResult{AssetType: "appengine.googleapis.com/Service", Location: "europe-west"},
+			want:  "europe-west1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getAssetLocation(tt.asset); got != tt.want {
+				t.Errorf("getAssetLocation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetLookupLocations(t *testing.T) {
+	gateway := &assetpb.ResourceSearchResult{AssetType: "gateway.networking.k8s.io/Gateway"}
+	if got := getLookupLocations("us-west1", "", gateway); !slices.Equal(got, []string{"us-west1", "global"}) {
+		t.Errorf("getLookupLocations() = %v", got)
+	}
+	if got := getLookupLocations("global", "", gateway); !slices.Equal(got, []string{"global"}) {
+		t.Errorf("getLookupLocations() = %v", got)
+	}
+	runURI := "//run.googleapis.com/projects/p/locations/us-west1/services/checkout"
+	if got := getLookupLocations("us-west1", runURI, nil); !slices.Equal(got, []string{"us-west1"}) {
+		t.Errorf("getLookupLocations() = %v", got)
+	}
+	gatewayURI := "//container.googleapis.com/projects/p/locations/us-west1/clusters/c/k8s/namespaces/shop/gateway.networking.k8s.io/gateways/web"
+	if got := getLookupLocations("us-west1", gatewayURI, nil); !slices.Equal(got, []string{"us-west1", "global"}) {
+		t.Errorf("getLookupLocations() without an asset = %v", got)
+	}
+}
+
+func TestSetAssetTypeRegistry(t *testing.T) {
+	defer func() { assetTypeRegistry = newAssetTypeRegistry() }()
+
+	if err := SetAssetTypeRegistry([]byte("assetTypes:\n  - assetType: foo.googleapis.com/Bar\n    appHubType: service")); err == nil {
+		t.Errorf("SetAssetTypeRegistry() expected error for invalid appHubType")
+	}
+
+	config := `
+assetTypes:
+  - assetType: run.googleapis.com/Job
+    appHubType: discoveredWorkload
+    enabled: false
+  - assetType: foo.googleapis.com/Bar
+    appHubType: discoveredWorkload
+    uri: "//foo.googleapis.com/projects/{{ .ProjectNumber }}/locations/{{ .Location }}/bars/{{ .Name }}"
+    fallbackLocations: [global]
+    enabled: true
+`
+	if err := SetAssetTypeRegistry([]byte(config)); err != nil {
+		t.Fatalf("SetAssetTypeRegistry() error = %v", err)
+	}
+
+	if slices.Contains(getIncludedAssetTypes(), "run.googleapis.com/Job") {
+		t.Errorf("getIncludedAssetTypes() must not contain a disabled asset type")
+	}
+	if !slices.Contains(getIncludedAssetTypes(), "foo.googleapis.com/Bar") {
+		t.Errorf("getIncludedAssetTypes() must contain the added asset type")
+	}
+	if got := identifyServiceOrWorkload("foo.googleapis.com/Bar"); got != "discoveredWorkload" {
+		t.Errorf("identifyServiceOrWorkload() = %v", got)
+	}
+
+	asset := &assetpb.ResourceSearchResult{
+		AssetType: "foo.googleapis.com/Bar",
+		Project:   "projects/12345",
+		Location:  "us-west1",
+	}
+	want := "//foo.googleapis.com/projects/12345/locations/us-west1/bars/b"
+	if got := fixResourceURI("//foo.googleapis.com/projects/p/locations/us-west1/bars/b", asset); got != want {
+		t.Errorf("fixResourceURI() = %v, want %v", got, want)
+	}
+}
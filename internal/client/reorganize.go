@@ -0,0 +1,318 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"internal/clilog"
+	"slices"
+	"strings"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// PlannedApplication is an application created by a merge or split
+type PlannedApplication struct {
+	ID       string `json:"id"`
+	Location string `json:"location"`
+	AttributeSummary
+
+	attributes *apphubpb.Attributes
+}
+
+// ReorganizationPlan describes the applications a merge or split creates, the
+// registrations it moves and the emptied applications it deletes
+type ReorganizationPlan struct {
+	Location string               `json:"location"`
+	Create   []PlannedApplication `json:"create"`
+	Moves    []MovedMember        `json:"moves"`
+	Delete   []string             `json:"delete"`
+
+	// registrations holds the registration of each move, in the same order
+	registrations []*registration
+}
+
+// addMove moves a registration to an application, unless it is already there
+func (p *ReorganizationPlan) addMove(r *registration, appName string) {
+	if r.appName == appName {
+		return
+	}
+	p.Moves = append(p.Moves, MovedMember{ApplicationMember: r.member(), From: r.appName, To: appName})
+	p.registrations = append(p.registrations, r)
+}
+
+// addCreate creates an application unless it is already planned
+func (p *ReorganizationPlan) addCreate(appID string, attributes *apphubpb.Attributes) {
+	for _, app := range p.Create {
+		if app.ID == appID {
+			return
+		}
+	}
+	p.Create = append(p.Create, PlannedApplication{
+		ID:               appID,
+		Location:         p.Location,
+		AttributeSummary: newAttributeSummary(attributes),
+		attributes:       attributes,
+	})
+}
+
+// PlanMerge returns the plan to move every service and workload of the source
+// applications into the target application and delete the emptied sources.
+// The sources must be in the same location. If the target does not exist, it
+// is created with the attributes read from attributesData or, when empty, the
+// combined attributes of the sources.
+func PlanMerge(managementProject string, sources []string, into string, locations []string,
+	attributesData []byte, includeUnmanaged bool,
+) (*ReorganizationPlan, error) {
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	var apps []*apphubpb.Application
+	for _, source := range sources {
+		app, err := findApplication(apphubClient, managementProject, source, locations)
+		if err != nil {
+			return nil, err
+		}
+		if !isManagedApp(app) && !includeUnmanaged {
+			return nil, errUnmanaged(app.GetName())
+		}
+		apps = append(apps, app)
+	}
+
+	plan := &ReorganizationPlan{Location: getNameSegment(apps[0].GetName(), "locations")}
+	for _, app := range apps[1:] {
+		if location := getNameSegment(app.GetName(), "locations"); location != plan.Location {
+			return nil, fmt.Errorf("applications %s and %s are in different locations", apps[0].GetName(), app.GetName())
+		}
+	}
+
+	target, err := getPlannedApplication(apphubClient, managementProject, plan.Location, into, includeUnmanaged)
+	if err != nil {
+		return nil, err
+	}
+	targetName := fmt.Sprintf("projects/%s/locations/%s/applications/%s", managementProject, plan.Location, into)
+	if target == nil {
+		attributes, err := newAttributesFromBytes(attributesData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse attributes: %w", err)
+		}
+		if attributes == nil {
+			var sourceAttributes []*apphubpb.Attributes
+			for _, app := range apps {
+				sourceAttributes = append(sourceAttributes, app.GetAttributes())
+			}
+			attributes = mergeAttributes(sourceAttributes...)
+		}
+		plan.addCreate(into, attributes)
+	}
+
+	for _, app := range apps {
+		registrations, err := listRegistrations(apphubClient, app)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range registrations {
+			plan.addMove(r, targetName)
+		}
+		if app.GetName() != targetName {
+			plan.Delete = append(plan.Delete, app.GetName())
+		}
+	}
+	return plan, nil
+}
+
+// PlanSplit returns the plan to move the services and workloads of an
+// application into one application per value of a CAIS label of the
+// underlying resources. The application is named after the label value.
+// Members without the label stay in place; the application is deleted when
+// every member is moved. Applications that do not exist are created with the
+// attributes read from attributesData or, when empty, those of the source.
+func PlanSplit(managementProject, name string, locations []string, labelKey string,
+	attributesData []byte, includeUnmanaged bool,
+) (*ReorganizationPlan, error) {
+	logger := clilog.GetLogger()
+
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	app, err := findApplication(apphubClient, managementProject, name, locations)
+	if err != nil {
+		return nil, err
+	}
+	if !isManagedApp(app) && !includeUnmanaged {
+		return nil, errUnmanaged(app.GetName())
+	}
+
+	attributes, err := newAttributesFromBytes(attributesData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse attributes: %w", err)
+	}
+	if attributes == nil {
+		attributes = app.GetAttributes()
+	}
+
+	registrations, err := listRegistrations(apphubClient, app)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]ApplicationMember, len(registrations))
+	for i, r := range registrations {
+		members[i] = r.member()
+	}
+	if err = enrichMembers(members); err != nil {
+		return nil, err
+	}
+
+	plan := &ReorganizationPlan{Location: getNameSegment(app.GetName(), "locations")}
+	targets := make(map[string]bool)
+	remaining := 0
+	for i, r := range registrations {
+		appID := getSplitAppID(members[i].Labels[labelKey])
+		if appID == "" {
+			logger.Warn("Member has no valid value for the split label, leaving it in place",
+				"member", members[i].Name, "label", labelKey)
+			remaining++
+			continue
+		}
+		targetName := fmt.Sprintf("projects/%s/locations/%s/applications/%s", managementProject, plan.Location, appID)
+		if targetName == app.GetName() {
+			remaining++
+			continue
+		}
+		if _, ok := targets[appID]; !ok {
+			target, err := getPlannedApplication(apphubClient, managementProject, plan.Location, appID, includeUnmanaged)
+			if err != nil {
+				return nil, err
+			}
+			targets[appID] = target != nil
+		}
+		if !targets[appID] {
+			plan.addCreate(appID, attributes)
+		}
+		plan.addMove(r, targetName)
+	}
+
+	if remaining == 0 && len(plan.Moves) > 0 {
+		plan.Delete = append(plan.Delete, app.GetName())
+	}
+	return plan, nil
+}
+
+// ApplyPlan creates the applications of a merge or split plan, moves the
+// registrations and deletes the emptied applications
+func ApplyPlan(managementProject string, plan *ReorganizationPlan) error {
+	logger := clilog.GetLogger()
+
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	for _, app := range plan.Create {
+		data, err := protojson.Marshal(app.attributes)
+		if err != nil {
+			return fmt.Errorf("failed to marshal attributes: %w", err)
+		}
+		if _, err = getOrCreateAppHubApplication(apphubClient, managementProject, app.Location, app.ID, data,
+			getProvenanceMarker("")); err != nil {
+			return fmt.Errorf("error creating application %s: %w", app.ID, err)
+		}
+	}
+
+	for i, r := range plan.registrations {
+		if plan.Moves[i].Name, err = moveRegistration(apphubClient, r, plan.Moves[i].To); err != nil {
+			return err
+		}
+	}
+
+	budget := newDeletionBudget(DEFAULT_DELETION_CONCURRENCY)
+	for _, appName := range plan.Delete {
+		logger.Info("Deleting emptied application", "application", appName)
+		if err = deleteApp(apphubClient, budget, managementProject, plan.Location, getNameSegment(appName, "applications")); err != nil {
+			return fmt.Errorf("error deleting application %s: %w", appName, err)
+		}
+	}
+	logger.Info("Successfully applied plan.")
+	return nil
+}
+
+// getPlannedApplication returns an existing target application or nil if it
+// has to be created
+func getPlannedApplication(apiclient appHubClient, projectID, location, appID string, includeUnmanaged bool) (*apphubpb.Application, error) {
+	appName := fmt.Sprintf("projects/%s/locations/%s/applications/%s", projectID, location, appID)
+	app, err := apiclient.GetApplication(context.Background(), &apphubpb.GetApplicationRequest{Name: appName})
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get application %s: %w", appID, err)
+	}
+	if !isManagedApp(app) && !includeUnmanaged {
+		return nil, errUnmanaged(appName)
+	}
+	return app, nil
+}
+
+// mergeAttributes combines the attributes of several applications. The first
+// criticality and environment set win; owners are combined by email.
+func mergeAttributes(attributes ...*apphubpb.Attributes) *apphubpb.Attributes {
+	merged := &apphubpb.Attributes{}
+	for _, a := range attributes {
+		if a == nil {
+			continue
+		}
+		if merged.Criticality == nil && a.GetCriticality() != nil {
+			merged.Criticality = proto.Clone(a.GetCriticality()).(*apphubpb.Criticality)
+		}
+		if merged.Environment == nil && a.GetEnvironment() != nil {
+			merged.Environment = proto.Clone(a.GetEnvironment()).(*apphubpb.Environment)
+		}
+		merged.DeveloperOwners = mergeOwners(merged.DeveloperOwners, a.GetDeveloperOwners())
+		merged.OperatorOwners = mergeOwners(merged.OperatorOwners, a.GetOperatorOwners())
+		merged.BusinessOwners = mergeOwners(merged.BusinessOwners, a.GetBusinessOwners())
+	}
+	return merged
+}
+
+func mergeOwners(owners, others []*apphubpb.ContactInfo) []*apphubpb.ContactInfo {
+	for _, other := range others {
+		if !slices.Contains(getOwnerEmails(owners), other.GetEmail()) {
+			owners = append(owners, proto.Clone(other).(*apphubpb.ContactInfo))
+		}
+	}
+	return owners
+}
+
+// getSplitAppID returns the application ID for a label value or an empty
+// string if the value cannot be used as an application ID
+func getSplitAppID(labelValue string) string {
+	appID := truncateName(strings.ReplaceAll(strings.ToLower(labelValue), "_", "-"))
+	if !isValidAppName(appID) {
+		return ""
+	}
+	return appID
+}
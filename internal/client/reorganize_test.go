@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+)
+
+func TestMergeAttributes(t *testing.T) {
+	checkout := &apphubpb.Attributes{
+		Criticality:     &apphubpb.Criticality{Type: apphubpb.Criticality_HIGH},
+		DeveloperOwners: []*apphubpb.ContactInfo{{Email: "dev@example.com"}},
+	}
+	checkoutAPI := &apphubpb.Attributes{
+		Criticality:     &apphubpb.Criticality{Type: apphubpb.Criticality_LOW},
+		Environment:     &apphubpb.Environment{Type: apphubpb.Environment_PRODUCTION},
+		DeveloperOwners: []*apphubpb.ContactInfo{{Email: "dev@example.com"}, {Email: "api@example.com"}},
+		BusinessOwners:  []*apphubpb.ContactInfo{{Email: "biz@example.com"}},
+	}
+
+	got := newAttributeSummary(mergeAttributes(checkout, nil, checkoutAPI))
+	want := AttributeSummary{
+		Criticality:     "HIGH",
+		Environment:     "PRODUCTION",
+		DeveloperOwners: []string{"dev@example.com", "api@example.com"},
+		BusinessOwners:  []string{"biz@example.com"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeAttributes() = %+v, want %+v", got, want)
+	}
+	if len(checkout.GetDeveloperOwners()) != 1 {
+		t.Errorf("mergeAttributes() must not modify its arguments")
+	}
+}
+
+func TestGetSplitAppID(t *testing.T) {
+	tests := []struct {
+		labelValue string
+		want       string
+	}{
+		{labelValue: "checkout", want: "checkout"},
+		{labelValue: "Checkout_API", want: "checkout-api"},
+		{labelValue: "1checkout", want: ""},
+		{labelValue: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.labelValue, func(t *testing.T) {
+			if got := getSplitAppID(tt.labelValue); got != tt.want {
+				t.Errorf("getSplitAppID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReorganizationPlan(t *testing.T) {
+	plan := &ReorganizationPlan{Location: "us-west1"}
+	target := "projects/p/locations/us-west1/applications/checkout"
+
+	plan.addCreate("checkout", nil)
+	plan.addCreate("checkout", nil)
+	if len(plan.Create) != 1 || plan.Create[0].Location != "us-west1" {
+		t.Errorf("addCreate() = %v, want a single application in us-west1", plan.Create)
+	}
+
+	moved := &registration{
+		appName: "projects/p/locations/us-west1/applications/checkout-api",
+		service: &apphubpb.Service{Name: "projects/p/locations/us-west1/applications/checkout-api/services/api"},
+	}
+	inPlace := &registration{
+		appName:  target,
+		workload: &apphubpb.Workload{Name: target + "/workloads/web"},
+	}
+	plan.addMove(moved, target)
+	plan.addMove(inPlace, target)
+	if len(plan.Moves) != 1 || len(plan.registrations) != 1 {
+		t.Fatalf("addMove() = %v, want only the member outside of the target", plan.Moves)
+	}
+	if plan.Moves[0].ID != "api" || plan.Moves[0].From != moved.appName || plan.Moves[0].To != target {
+		t.Errorf("addMove() = %+v", plan.Moves[0])
+	}
+}
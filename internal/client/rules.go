@@ -0,0 +1,409 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"internal/clilog"
+	"regexp"
+	"strings"
+	"text/template"
+
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+	"gopkg.in/yaml.v3"
+)
+
+var getProjectIDFunc = getProjectID
+
+// ruleSet is the top level structure of a rules file.
+type ruleSet struct {
+	Rules []rule `yaml:"rules"`
+
+	projectIDs map[string]string
+}
+
+// rule groups the assets it matches into the application named by App.
+// Rules are evaluated in order and the first match wins.
+type rule struct {
+	Name       string                 `yaml:"name"`
+	Match      ruleMatch              `yaml:"match"`
+	App        string                 `yaml:"app"`
+	Attributes map[string]interface{} `yaml:"attributes,omitempty"`
+
+	appTemplate    *template.Template
+	attributesData []byte
+	assetTypes     []*regexp.Regexp
+	labels         map[string]*regexp.Regexp
+	tags           map[string]*regexp.Regexp
+	name           *regexp.Regexp
+	projects       []*regexp.Regexp
+	locations      []*regexp.Regexp
+}
+
+// ruleMatch holds the match criteria of a rule. All criteria that are set
+// must be satisfied. Values are globs (* and ?) unless they are enclosed
+// in slashes, in which case they are treated as regular expressions.
+type ruleMatch struct {
+	AssetTypes []string          `yaml:"assetTypes,omitempty"`
+	Labels     map[string]string `yaml:"labels,omitempty"`
+	Tags       map[string]string `yaml:"tags,omitempty"`
+	Name       string            `yaml:"name,omitempty"`
+	Projects   []string          `yaml:"projects,omitempty"`
+	Locations  []string          `yaml:"locations,omitempty"`
+}
+
+// ruleTemplateData is the data made available to the app name template.
+type ruleTemplateData struct {
+	Name          string
+	AssetType     string
+	Location      string
+	Namespace     string
+	ProjectNumber string
+	Labels        map[string]string
+	Tags          map[string]string
+
+	asset *assetpb.ResourceSearchResult
+	rules *ruleSet
+}
+
+// Project returns the project id of the asset.
+func (d ruleTemplateData) Project() string {
+	return d.rules.projectID(d.asset)
+}
+
+// parseRules reads a YAML rules file and compiles the patterns and templates
+// of every rule.
+func parseRules(data []byte) (*ruleSet, error) {
+	rs := &ruleSet{projectIDs: make(map[string]string)}
+	if err := yaml.Unmarshal(data, rs); err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+
+	if len(rs.Rules) == 0 {
+		return nil, fmt.Errorf("rules file must contain at least one rule")
+	}
+
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		if err := r.compile(); err != nil {
+			return nil, fmt.Errorf("invalid rule %s: %w", r.Name, err)
+		}
+	}
+	return rs, nil
+}
+
+func (r *rule) compile() (err error) {
+	if r.App == "" {
+		return fmt.Errorf("app is a required field")
+	}
+
+	if r.appTemplate, err = template.New(r.Name).Option("missingkey=zero").Parse(r.App); err != nil {
+		return fmt.Errorf("invalid app template: %w", err)
+	}
+
+	if len(r.Attributes) > 0 {
+		if r.attributesData, err = json.Marshal(r.Attributes); err != nil {
+			return fmt.Errorf("invalid attributes: %w", err)
+		}
+		if _, err = newAttributesFromBytes(r.attributesData); err != nil {
+			return fmt.Errorf("invalid attributes: %w", err)
+		}
+	}
+
+	if r.assetTypes, err = compilePatterns(r.Match.AssetTypes); err != nil {
+		return err
+	}
+	if r.projects, err = compilePatterns(r.Match.Projects); err != nil {
+		return err
+	}
+	if r.locations, err = compilePatterns(r.Match.Locations); err != nil {
+		return err
+	}
+	if r.Match.Name != "" {
+		if r.name, err = compilePattern(r.Match.Name); err != nil {
+			return err
+		}
+	}
+	if r.labels, err = compilePatternMap(r.Match.Labels); err != nil {
+		return err
+	}
+	if r.tags, err = compilePatternMap(r.Match.Tags); err != nil {
+		return err
+	}
+	return nil
+}
+
+// compilePattern converts a glob or a /regex/ into a regular expression.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+		}
+		return re, nil
+	}
+	glob := regexp.QuoteMeta(pattern)
+	glob = strings.ReplaceAll(glob, `\*`, ".*")
+	glob = strings.ReplaceAll(glob, `\?`, ".")
+	return regexp.MustCompile("^" + glob + "$"), nil
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, p := range patterns {
+		re, err := compilePattern(p)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+func compilePatternMap(patterns map[string]string) (map[string]*regexp.Regexp, error) {
+	compiled := make(map[string]*regexp.Regexp)
+	for k, p := range patterns {
+		re, err := compilePattern(p)
+		if err != nil {
+			return nil, err
+		}
+		compiled[k] = re
+	}
+	return compiled, nil
+}
+
+func matchAny(patterns []*regexp.Regexp, values ...string) bool {
+	for _, re := range patterns {
+		for _, v := range values {
+			if re.MatchString(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matches returns true when the asset satisfies every criteria of the rule.
+func (r *rule) matches(rs *ruleSet, asset *assetpb.ResourceSearchResult) bool {
+	if len(r.assetTypes) > 0 && !matchAny(r.assetTypes, asset.GetAssetType()) {
+		return false
+	}
+	if len(r.locations) > 0 && !matchAny(r.locations, asset.GetLocation()) {
+		return false
+	}
+	if r.name != nil && !r.name.MatchString(getAssetShortName(asset.GetName())) {
+		return false
+	}
+	for key, re := range r.labels {
+		value, ok := asset.GetLabels()[key]
+		if !ok || !re.MatchString(value) {
+			return false
+		}
+	}
+	if len(r.tags) > 0 {
+		assetTags := getAssetTags(asset)
+		for key, re := range r.tags {
+			value, ok := assetTags[key]
+			if !ok || !re.MatchString(value) {
+				return false
+			}
+		}
+	}
+	if len(r.projects) > 0 {
+		projectNumber := strings.TrimPrefix(asset.GetProject(), "projects/")
+		if !matchAny(r.projects, projectNumber) && !matchAny(r.projects, rs.projectID(asset)) {
+			return false
+		}
+	}
+	return true
+}
+
+// appName renders the application name template of the rule for an asset.
+func (r *rule) appName(rs *ruleSet, asset *assetpb.ResourceSearchResult) (string, error) {
+	data := ruleTemplateData{
+		Name:          getAssetShortName(asset.GetName()),
+		AssetType:     asset.GetAssetType(),
+		Location:      asset.GetLocation(),
+		Namespace:     getAssetNamespace(asset),
+		ProjectNumber: strings.TrimPrefix(asset.GetProject(), "projects/"),
+		Labels:        asset.GetLabels(),
+		Tags:          getAssetTags(asset),
+		asset:         asset,
+		rules:         rs,
+	}
+
+	var buf bytes.Buffer
+	if err := r.appTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render app name: %w", err)
+	}
+
+	appName := strings.ToLower(strings.TrimSpace(buf.String()))
+	if !isValidAppName(appName) {
+		return "", fmt.Errorf("rendered app name %q is not valid", appName)
+	}
+	return appName, nil
+}
+
+// evaluate returns the index of the first rule that matches the asset
+// along with the rendered application name.
+func (rs *ruleSet) evaluate(asset *assetpb.ResourceSearchResult) (int, string, error) {
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		if !r.matches(rs, asset) {
+			continue
+		}
+		appName, err := r.appName(rs, asset)
+		if err != nil {
+			return -1, "", fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+		return i, appName, nil
+	}
+	return -1, "", fmt.Errorf("no rule matched")
+}
+
+func (rs *ruleSet) projectID(asset *assetpb.ResourceSearchResult) string {
+	project := asset.GetProject()
+	if id, ok := rs.projectIDs[project]; ok {
+		return id
+	}
+	id := getProjectIDFunc(project, context.Background())
+	rs.projectIDs[project] = id
+	return id
+}
+
+// getAssetShortName returns the last segment of the full resource name
+func getAssetShortName(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// getAssetNamespace returns the Kubernetes namespace of an asset, if any
+func getAssetNamespace(asset *assetpb.ResourceSearchResult) string {
+	parent := asset.GetParentFullResourceName()
+	if !strings.Contains(parent, "/namespaces/") {
+		return ""
+	}
+	return getAssetShortName(parent)
+}
+
+// getAssetTags returns the short names of the tags and effective tags of an asset
+func getAssetTags(asset *assetpb.ResourceSearchResult) map[string]string {
+	tags := make(map[string]string)
+	for _, effectiveTagDetails := range asset.GetEffectiveTags() {
+		for _, tag := range effectiveTagDetails.GetEffectiveTags() {
+			tags[getAssetShortName(tag.GetTagKey())] = getAssetShortName(tag.GetTagValue())
+		}
+	}
+	// direct tags take precedence over inherited tags
+	for _, tag := range asset.GetTags() {
+		tags[getAssetShortName(tag.GetTagKey())] = getAssetShortName(tag.GetTagValue())
+	}
+	return tags
+}
+
+// GenerateFromRules searches CAIS and groups assets into applications using an
+// ordered list of rules. The first rule to match an asset decides its application.
+// Assets that do not match any rule are returned so they can be reported.
+func GenerateFromRules(parent, managementProject string, locations []string, rulesData, attributesData []byte,
+	reportOnly bool,
+) (map[string][]string, []string, error) {
+	logger := clilog.GetLogger()
+	var appLocation string
+	var unmatchedAssets []string
+	generatedApplications := make(map[string][]string)
+
+	rs, err := parseRules(rulesData)
+	if err != nil {
+		return generatedApplications, nil, err
+	}
+
+	assetTypes := getSearchAssetTypes(getSupportedAssetTypes(), nil)
+
+	logger.Info("Running CAIS Search with location and Filters")
+	assets, err := searchAssetsFunc(parent, "", "", "", "", "", locations, assetTypes)
+	if err != nil {
+		return generatedApplications, nil, fmt.Errorf("error searching assets: %w", err)
+	}
+
+	if len(assets) == 0 {
+		logger.Warn("No assets found that matched the filter")
+		return generatedApplications, nil, fmt.Errorf("no assets found that matched the filter")
+	}
+
+	logger.Info("Found assets to process", "count", len(assets))
+
+	// group the assets by the rule that matched them
+	ruleAssets := make([][]*assetpb.ResourceSearchResult, len(rs.Rules))
+	assetAppNames := make(map[string]string)
+	for _, asset := range assets {
+		i, appName, err := rs.evaluate(asset)
+		if err != nil {
+			logger.Warn("Asset did not match any rule", "assetName", asset.Name, "reason", err)
+			unmatchedAssets = append(unmatchedAssets, asset.Name)
+			continue
+		}
+		logger.Info("Asset matched rule", "assetName", asset.Name, "rule", rs.Rules[i].Name, "application", appName)
+		ruleAssets[i] = append(ruleAssets[i], asset)
+		assetAppNames[asset.Name] = appName
+	}
+
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return generatedApplications, unmatchedAssets, fmt.Errorf("error getting apphub client: %w", err)
+	}
+
+	defer closeAppHubClient(apphubClient)
+
+	if len(locations) > 1 {
+		appLocation = "global"
+	} else {
+		appLocation = locations[0]
+	}
+
+	appNameFunc := func(asset *assetpb.ResourceSearchResult) string {
+		return assetAppNames[asset.Name]
+	}
+
+	var matchedAssets []*assetpb.ResourceSearchResult
+	for i := range ruleAssets {
+		matchedAssets = append(matchedAssets, ruleAssets[i]...)
+	}
+	unattached := checkServiceProjects(apphubClient, managementProject, matchedAssets, attachServiceProjects && !reportOnly)
+
+	for i := range rs.Rules {
+		if len(ruleAssets[i]) == 0 {
+			continue
+		}
+		ruleAttributesData := attributesData
+		if len(rs.Rules[i].attributesData) > 0 {
+			ruleAttributesData = rs.Rules[i].attributesData
+		}
+		ruleApplications, err := processAssets(ruleAssets[i], apphubClient, managementProject, appLocation,
+			ruleAttributesData, reportOnly, rs.Rules[i].Name, unattached, appNameFunc)
+		for appName, values := range ruleApplications {
+			generatedApplications[appName] = append(generatedApplications[appName], values...)
+		}
+		if err != nil {
+			return generatedApplications, unmatchedAssets, err
+		}
+	}
+
+	return generatedApplications, unmatchedAssets, nil
+}
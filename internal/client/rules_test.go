@@ -0,0 +1,271 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+	"github.com/googleapis/gax-go/v2"
+)
+
+const testRules = `
+rules:
+  - name: payments-sql
+    match:
+      assetTypes: ["sqladmin.googleapis.com/Instance"]
+      name: "pay-*"
+    app: payments
+    attributes:
+      criticality:
+        type: MISSION_CRITICAL
+  - name: appid-label
+    match:
+      labels:
+        appid: "*"
+    app: '{{ index .Labels "appid" }}'
+  - name: project-regex
+    match:
+      projects: ["/^team-[a-z]+$/"]
+    app: "{{ .Project }}"
+  - name: gke-namespaces
+    match:
+      assetTypes: ["apps.k8s.io/*"]
+    app: "{{ .Namespace }}"
+`
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name:    "Valid rules",
+			data:    testRules,
+			wantErr: false,
+		},
+		{
+			name:    "No rules",
+			data:    "rules: []",
+			wantErr: true,
+		},
+		{
+			name:    "Missing app",
+			data:    "rules:\n  - name: a\n    match:\n      name: foo",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid template",
+			data:    "rules:\n  - app: '{{ .Name'",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid regex",
+			data:    "rules:\n  - app: foo\n    match:\n      name: '/[a-/'",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid attributes",
+			data:    "rules:\n  - app: foo\n    attributes:\n      criticality:\n        type: VERY",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseRules([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRuleSetEvaluate(t *testing.T) {
+	getProjectIDFunc = func(project string, ctx context.Context) string {
+		if project == "projects/222" {
+			return "team-orders"
+		}
+		return "other"
+	}
+	defer func() { getProjectIDFunc = getProjectID }()
+
+	rs, err := parseRules([]byte(testRules))
+	if err != nil {
+		t.Fatalf("parseRules() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		asset    *assetpb.ResourceSearchResult
+		wantRule int
+		wantApp  string
+		wantErr  bool
+	}{
+		{
+			name: "First rule wins over label rule",
+			asset: &assetpb.ResourceSearchResult{
+				Name:      "//cloudsql.googleapis.com/projects/p/instances/pay-db",
+				AssetType: "sqladmin.googleapis.com/Instance",
+				Project:   "projects/111",
+				Labels:    map[string]string{"appid": "billing"},
+			},
+			wantRule: 0,
+			wantApp:  "payments",
+		},
+		{
+			name: "Label template",
+			asset: &assetpb.ResourceSearchResult{
+				Name:      "//run.googleapis.com/projects/p/locations/us-west1/services/checkout",
+				AssetType: "run.googleapis.com/Service",
+				Project:   "projects/111",
+				Labels:    map[string]string{"appid": "Checkout"},
+			},
+			wantRule: 1,
+			wantApp:  "checkout",
+		},
+		{
+			name: "Project regex and project template",
+			asset: &assetpb.ResourceSearchResult{
+				Name:      "//storage.googleapis.com/bucket",
+				AssetType: "storage.googleapis.com/Bucket",
+				Project:   "projects/222",
+			},
+			wantRule: 2,
+			wantApp:  "team-orders",
+		},
+		{
+			name: "Kubernetes namespace",
+			asset: &assetpb.ResourceSearchResult{
+				Name:                   "//container.googleapis.com/projects/p/locations/us-west1/clusters/c/k8s/namespaces/shop/apps/deployments/web",
+				AssetType:              "apps.k8s.io/Deployment",
+				Project:                "projects/111",
+				ParentFullResourceName: "//container.googleapis.com/projects/p/locations/us-west1/clusters/c/k8s/namespaces/shop",
+			},
+			wantRule: 3,
+			wantApp:  "shop",
+		},
+		{
+			name: "Unmatched",
+			asset: &assetpb.ResourceSearchResult{
+				Name:      "//storage.googleapis.com/bucket",
+				AssetType: "storage.googleapis.com/Bucket",
+				Project:   "projects/111",
+			},
+			wantRule: -1,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRule, gotApp, err := rs.evaluate(tt.asset)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("evaluate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotRule != tt.wantRule {
+				t.Errorf("evaluate() rule = %v, want %v", gotRule, tt.wantRule)
+			}
+			if gotApp != tt.wantApp {
+				t.Errorf("evaluate() app = %v, want %v", gotApp, tt.wantApp)
+			}
+		})
+	}
+}
+
+func TestCompilePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{pattern: "pay-*", value: "pay-db", want: true},
+		{pattern: "pay-*", value: "xpay-db", want: false},
+		{pattern: "db?", value: "db1", want: true},
+		{pattern: "a.b", value: "axb", want: false},
+		{pattern: "/^us-.*1$/", value: "us-east1", want: true},
+		{pattern: "/^us-.*1$/", value: "us-east4", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.value, func(t *testing.T) {
+			re, err := compilePattern(tt.pattern)
+			if err != nil {
+				t.Fatalf("compilePattern() error = %v", err)
+			}
+			if got := re.MatchString(tt.value); got != tt.want {
+				t.Errorf("MatchString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateFromRulesChecksServiceProjectsOnce(t *testing.T) {
+	searchAssetsFunc = func(parent, labelKey, labelValue, tagKey, tagValue, contains string, locations, assetTypes []string) ([]*assetpb.ResourceSearchResult, error) {
+		return []*assetpb.ResourceSearchResult{
+			{
+				Name:      "//cloudsql.googleapis.com/projects/host/instances/pay-db",
+				AssetType: "sqladmin.googleapis.com/Instance",
+				Project:   "projects/111",
+				Location:  "us-west1",
+			},
+			{
+				Name:      "//run.googleapis.com/projects/host/locations/us-west1/services/checkout",
+				AssetType: "run.googleapis.com/Service",
+				Project:   "projects/111",
+				Location:  "us-west1",
+				Labels:    map[string]string{"appid": "shop"},
+			},
+		}, nil
+	}
+	getAppHubClientFunc = func() (appHubClient, error) {
+		return &mockAppHubClient{
+			lookupDiscoveredServiceFunc: func(ctx context.Context, req *apphubpb.LookupDiscoveredServiceRequest, opts ...gax.CallOption) (*apphubpb.LookupDiscoveredServiceResponse, error) {
+				return &apphubpb.LookupDiscoveredServiceResponse{
+					DiscoveredService: &apphubpb.DiscoveredService{Name: "projects/host/locations/us-west1/discoveredServices/ds"},
+				}, nil
+			},
+		}, nil
+	}
+	getProjectIDFunc = func(project string, ctx context.Context) string {
+		return "host"
+	}
+	checks := 0
+	listAttachedProjectsFunc = func(apiclient appHubClient, managementProject string) (map[string]bool, error) {
+		checks++
+		return map[string]bool{}, nil
+	}
+	defer func() {
+		searchAssetsFunc = searchAssets
+		getAppHubClientFunc = getAppHubClient
+		getProjectIDFunc = getProjectID
+		listAttachedProjectsFunc = listAttachedProjects
+	}()
+
+	generated, _, err := GenerateFromRules("projects/host", "host", []string{"us-west1"}, []byte(testRules), nil, true)
+	if err != nil {
+		t.Fatalf("GenerateFromRules() error = %v", err)
+	}
+	if len(generated) != 2 {
+		t.Errorf("GenerateFromRules() = %v, want payments and shop", generated)
+	}
+	if checks != 1 {
+		t.Errorf("GenerateFromRules() checked the service projects %d times, want once", checks)
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"internal/clilog"
+	"sort"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceProject is a project holding discovered resources and its attachment
+// to the management project
+type ServiceProject struct {
+	Project string `json:"project"`
+	Assets  int    `json:"assets"`
+	// Status is one of ALREADY_ATTACHED, ATTACHED, NOT_ATTACHED, ATTACHED_ELSEWHERE or FAILED
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+var attachServiceProjects bool
+
+// SetAttachProjects allows generate to attach the projects of the assets it
+// processes to the management project as service projects
+func SetAttachProjects(attach bool) {
+	attachServiceProjects = attach
+}
+
+// AttachServiceProjects attaches projects to the management project as service
+// projects. When projects is empty, the projects of the assets found under
+// parent in the locations are used. With dryRun, the projects that are not
+// attached are reported and nothing is attached.
+func AttachServiceProjects(parent, managementProject string, locations, projects []string, dryRun bool) ([]ServiceProject, error) {
+	logger := clilog.GetLogger()
+
+	var assets []*assetpb.ResourceSearchResult
+	if len(projects) == 0 {
+		var err error
+		logger.Info("Searching assets to find their projects", "parent", parent)
+		if assets, err = searchAssetsFunc(parent, "", "", "", "", "", locations,
+			getSearchAssetTypes(getIncludedAssetTypes(), nil)); err != nil {
+			return nil, fmt.Errorf("error searching assets: %w", err)
+		}
+	}
+	for _, project := range projects {
+		assets = append(assets, &assetpb.ResourceSearchResult{Project: "projects/" + project})
+	}
+
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	attached, err := listAttachedProjects(apphubClient, managementProject)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceProjects, _ := getServiceProjects(attached, managementProject, assets)
+	for i := range serviceProjects {
+		if serviceProjects[i].Status == "NOT_ATTACHED" {
+			serviceProjects[i] = resolveServiceProject(apphubClient, managementProject, serviceProjects[i], !dryRun)
+		}
+	}
+	return serviceProjects, nil
+}
+
+var listAttachedProjectsFunc = listAttachedProjects
+
+// checkServiceProjects finds the projects of assets that are not attached to
+// the management project, attaches them when enabled and returns the asset
+// projects that remain unattached. Attachments that cannot be listed are
+// logged, the lookup of each asset reports the problem.
+func checkServiceProjects(apiclient appHubClient, managementProject string, assets []*assetpb.ResourceSearchResult, attach bool) map[string]bool {
+	logger := clilog.GetLogger()
+	unattached := make(map[string]bool)
+
+	attached, err := listAttachedProjectsFunc(apiclient, managementProject)
+	if err != nil {
+		logger.Warn("Unable to check the service projects of the management project", "error", err)
+		return unattached
+	}
+
+	serviceProjects, projectIDs := getServiceProjects(attached, managementProject, assets)
+	for _, serviceProject := range serviceProjects {
+		if serviceProject.Status != "NOT_ATTACHED" {
+			continue
+		}
+		serviceProject = resolveServiceProject(apiclient, managementProject, serviceProject, attach)
+		if serviceProject.Status == "ATTACHED" {
+			continue
+		}
+		logger.Warn("Project is not attached to the management project as a service project, "+
+			"its resources cannot be registered. Attach it with apps attach-projects or generate --attach-projects",
+			"project", serviceProject.Project, "assets", serviceProject.Assets, "error", serviceProject.Error)
+		for project, projectID := range projectIDs {
+			if projectID == serviceProject.Project {
+				unattached[project] = true
+			}
+		}
+	}
+	return unattached
+}
+
+// listAttachedProjects returns the service projects of the management project,
+// by project name and by the name built from the attachment ID, which is the
+// project ID
+func listAttachedProjects(apiclient appHubClient, managementProject string) (map[string]bool, error) {
+	ctx := context.Background()
+	attached := make(map[string]bool)
+
+	listAttachments := apiclient.ListServiceProjectAttachments(ctx, &apphubpb.ListServiceProjectAttachmentsRequest{
+		Parent: fmt.Sprintf("projects/%s/locations/global", managementProject),
+	})
+	for {
+		attachment, err := listAttachments.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list service project attachments: %w", err)
+		}
+		attached[attachment.GetServiceProject()] = true
+		attached["projects/"+getNameSegment(attachment.GetName(), "serviceProjectAttachments")] = true
+	}
+	return attached, nil
+}
+
+// getServiceProjects returns the projects of the assets, by project ID, with
+// ALREADY_ATTACHED or NOT_ATTACHED, and the project ID of each asset project.
+// The management project is left out.
+func getServiceProjects(attached map[string]bool, managementProject string, assets []*assetpb.ResourceSearchResult) ([]ServiceProject, map[string]string) {
+	counts := make(map[string]int)
+	statuses := make(map[string]string)
+	projectIDs := make(map[string]string)
+
+	for _, asset := range assets {
+		projectID, ok := projectIDs[asset.GetProject()]
+		if !ok {
+			// resolving the project number is a Resource Manager call, once per project
+			projectID = getProjectIDFunc(asset.GetProject(), context.Background())
+			projectIDs[asset.GetProject()] = projectID
+		}
+		if projectID == "" || projectID == "unknown" || projectID == managementProject {
+			continue
+		}
+		counts[projectID]++
+		if _, ok := statuses[projectID]; ok {
+			continue
+		}
+		if attached[asset.GetProject()] || attached["projects/"+projectID] {
+			statuses[projectID] = "ALREADY_ATTACHED"
+		} else {
+			statuses[projectID] = "NOT_ATTACHED"
+		}
+	}
+
+	var serviceProjects []ServiceProject
+	for projectID, state := range statuses {
+		serviceProjects = append(serviceProjects, ServiceProject{Project: projectID, Assets: counts[projectID], Status: state})
+	}
+	sort.Slice(serviceProjects, func(i, j int) bool {
+		return serviceProjects[i].Project < serviceProjects[j].Project
+	})
+	return serviceProjects, projectIDs
+}
+
+// resolveServiceProject attaches a project that is not attached or, when
+// attach is false, reports whether it is attached to another host project
+func resolveServiceProject(apiclient appHubClient, managementProject string, serviceProject ServiceProject, attach bool) ServiceProject {
+	ctx := context.Background()
+
+	response, err := apiclient.LookupServiceProjectAttachment(ctx, &apphubpb.LookupServiceProjectAttachmentRequest{
+		Name: fmt.Sprintf("projects/%s/locations/global", serviceProject.Project),
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); !ok || st.Code() != codes.NotFound {
+			serviceProject.Status, serviceProject.Error = "FAILED", err.Error()
+			return serviceProject
+		}
+	} else if attachment := response.GetServiceProjectAttachment(); attachment != nil {
+		serviceProject.Status = "ATTACHED_ELSEWHERE"
+		serviceProject.Error = fmt.Sprintf("attached to host project %s", getNameSegment(attachment.GetName(), "projects"))
+		return serviceProject
+	}
+
+	if !attach {
+		return serviceProject
+	}
+	if err = attachProject(apiclient, managementProject, serviceProject.Project); err != nil {
+		serviceProject.Status, serviceProject.Error = "FAILED", err.Error()
+		return serviceProject
+	}
+	serviceProject.Status = "ATTACHED"
+	return serviceProject
+}
+
+// attachProject attaches a project to the management project as a service project
+func attachProject(apiclient appHubClient, managementProject, projectID string) error {
+	ctx := context.Background()
+	logger := clilog.GetLogger()
+
+	logger.Info("Attaching service project", "project", projectID, "managementProject", managementProject)
+	op, err := apiclient.CreateServiceProjectAttachment(ctx, &apphubpb.CreateServiceProjectAttachmentRequest{
+		Parent:                     fmt.Sprintf("projects/%s/locations/global", managementProject),
+		ServiceProjectAttachmentId: projectID,
+		ServiceProjectAttachment: &apphubpb.ServiceProjectAttachment{
+			ServiceProject: "projects/" + projectID,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start service project attachment: %w", err)
+	}
+	attachment, err := op.Wait(ctx)
+	if err != nil {
+		return fmt.Errorf("service project attachment failed during wait: %w", err)
+	}
+	recordMutation("ATTACH_PROJECT", attachment.GetName(), nil, attachment)
+	logger.Info("Service project successfully attached.", "project", projectID)
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetServiceProjects(t *testing.T) {
+	projectIDs := map[string]string{
+		"projects/111": "host",
+		"projects/222": "orders",
+		"projects/333": "payments",
+	}
+	getProjectIDFunc = func(project string, ctx context.Context) string {
+		return projectIDs[project]
+	}
+	defer func() { getProjectIDFunc = getProjectID }()
+
+	assets := []*assetpb.ResourceSearchResult{
+		{Project: "projects/111"},
+		{Project: "projects/222"},
+		{Project: "projects/333"},
+		{Project: "projects/333"},
+	}
+	attached := map[string]bool{"projects/orders": true}
+
+	got, gotIDs := getServiceProjects(attached, "host", assets)
+	want := []ServiceProject{
+		{Project: "orders", Assets: 1, Status: "ALREADY_ATTACHED"},
+		{Project: "payments", Assets: 2, Status: "NOT_ATTACHED"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getServiceProjects() = %+v, want %+v", got, want)
+	}
+	if !reflect.DeepEqual(gotIDs, projectIDs) {
+		t.Errorf("getServiceProjects() project IDs = %v, want %v", gotIDs, projectIDs)
+	}
+}
+
+func TestResolveServiceProjectDryRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		lookup     func(ctx context.Context, req *apphubpb.LookupServiceProjectAttachmentRequest, opts ...gax.CallOption) (*apphubpb.LookupServiceProjectAttachmentResponse, error)
+		wantStatus string
+	}{
+		{
+			name: "Not attached",
+			lookup: func(ctx context.Context, req *apphubpb.LookupServiceProjectAttachmentRequest, opts ...gax.CallOption) (*apphubpb.LookupServiceProjectAttachmentResponse, error) {
+				if req.GetName() != "projects/payments/locations/global" {
+					t.Errorf("LookupServiceProjectAttachment() name = %s", req.GetName())
+				}
+				return &apphubpb.LookupServiceProjectAttachmentResponse{}, nil
+			},
+			wantStatus: "NOT_ATTACHED",
+		},
+		{
+			name: "Attached to another host project",
+			lookup: func(ctx context.Context, req *apphubpb.LookupServiceProjectAttachmentRequest, opts ...gax.CallOption) (*apphubpb.LookupServiceProjectAttachmentResponse, error) {
+				return &apphubpb.LookupServiceProjectAttachmentResponse{
+					ServiceProjectAttachment: &apphubpb.ServiceProjectAttachment{
+						Name: "projects/other-host/locations/global/serviceProjectAttachments/payments",
+					},
+				}, nil
+			},
+			wantStatus: "ATTACHED_ELSEWHERE",
+		},
+		{
+			name: "Permission denied",
+			lookup: func(ctx context.Context, req *apphubpb.LookupServiceProjectAttachmentRequest, opts ...gax.CallOption) (*apphubpb.LookupServiceProjectAttachmentResponse, error) {
+				return nil, status.Error(codes.PermissionDenied, "permission denied")
+			},
+			wantStatus: "FAILED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &mockAppHubClient{lookupAttachmentFunc: tt.lookup}
+			got := resolveServiceProject(mockClient, "host", ServiceProject{Project: "payments", Status: "NOT_ATTACHED"}, false)
+			if got.Status != tt.wantStatus {
+				t.Errorf("resolveServiceProject() status = %s, want %s (error %s)", got.Status, tt.wantStatus, got.Error)
+			}
+		})
+	}
+}
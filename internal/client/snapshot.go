@@ -0,0 +1,308 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"internal/clilog"
+	"os"
+	"time"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Snapshot is the state of applications saved before they are deleted
+type Snapshot struct {
+	Timestamp         string                `json:"timestamp"`
+	ManagementProject string                `json:"managementProject"`
+	Applications      []SnapshotApplication `json:"applications"`
+}
+
+// SnapshotApplication is an application with its services and workloads, in
+// the JSON representation of the App Hub API
+type SnapshotApplication struct {
+	Application json.RawMessage   `json:"application"`
+	Services    []json.RawMessage `json:"services,omitempty"`
+	Workloads   []json.RawMessage `json:"workloads,omitempty"`
+}
+
+// RestoreResult is the outcome of restoring a service or workload
+type RestoreResult struct {
+	ApplicationMember
+	Application string `json:"application"`
+	// Status is one of RESTORED, MISSING, REGISTERED or FAILED
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// RestoreReport lists the applications created and the outcome of every member
+type RestoreReport struct {
+	Created  []string        `json:"created"`
+	Existing []string        `json:"existing"`
+	Members  []RestoreResult `json:"members"`
+}
+
+// WriteSnapshot saves the definition, attributes, services and workloads of
+// the named applications to a file
+func WriteSnapshot(managementProject string, appNames []string, path string) error {
+	ctx := context.Background()
+	logger := clilog.GetLogger()
+
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	snapshot := Snapshot{
+		Timestamp:         time.Now().UTC().Format(time.RFC3339),
+		ManagementProject: managementProject,
+	}
+	for _, appName := range appNames {
+		app, err := apphubClient.GetApplication(ctx, &apphubpb.GetApplicationRequest{Name: appName})
+		if err != nil {
+			return fmt.Errorf("failed to get application %s: %w", appName, err)
+		}
+		registrations, err := listRegistrations(apphubClient, app)
+		if err != nil {
+			return err
+		}
+		a, err := newSnapshotApplication(app, registrations)
+		if err != nil {
+			return err
+		}
+		snapshot.Applications = append(snapshot.Applications, a)
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	logger.Info("Snapshot written", "path", path, "applications", len(appNames))
+	return nil
+}
+
+// ReadSnapshot reads a snapshot written by WriteSnapshot
+func ReadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	snapshot := &Snapshot{}
+	if err = json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+func newSnapshotApplication(app *apphubpb.Application, registrations []*registration) (SnapshotApplication, error) {
+	var a SnapshotApplication
+	var err error
+
+	if a.Application, err = protojson.Marshal(app); err != nil {
+		return a, fmt.Errorf("failed to marshal application: %w", err)
+	}
+	for _, r := range registrations {
+		if r.service != nil {
+			data, err := protojson.Marshal(r.service)
+			if err != nil {
+				return a, fmt.Errorf("failed to marshal service: %w", err)
+			}
+			a.Services = append(a.Services, data)
+		} else {
+			data, err := protojson.Marshal(r.workload)
+			if err != nil {
+				return a, fmt.Errorf("failed to marshal workload: %w", err)
+			}
+			a.Workloads = append(a.Workloads, data)
+		}
+	}
+	return a, nil
+}
+
+// unmarshal returns the application and its registrations
+func (a SnapshotApplication) unmarshal() (*apphubpb.Application, []*registration, error) {
+	// skip fields added to the API after the snapshot was taken
+	opts := protojson.UnmarshalOptions{DiscardUnknown: true}
+
+	app := &apphubpb.Application{}
+	if err := opts.Unmarshal(a.Application, app); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse application: %w", err)
+	}
+
+	var registrations []*registration
+	for _, data := range a.Services {
+		service := &apphubpb.Service{}
+		if err := opts.Unmarshal(data, service); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse service: %w", err)
+		}
+		registrations = append(registrations, &registration{appName: app.GetName(), service: service})
+	}
+	for _, data := range a.Workloads {
+		workload := &apphubpb.Workload{}
+		if err := opts.Unmarshal(data, workload); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse workload: %w", err)
+		}
+		registrations = append(registrations, &registration{appName: app.GetName(), workload: workload})
+	}
+	return app, registrations, nil
+}
+
+// RestoreSnapshot recreates the applications of a snapshot that no longer
+// exist and registers their services and workloads again. Members whose
+// discovered resource no longer exists are reported as MISSING. With dryRun,
+// the discovered resources are looked up but nothing is created.
+func RestoreSnapshot(path string, dryRun bool) (*RestoreReport, error) {
+	snapshot, err := ReadSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	report := &RestoreReport{}
+	for _, a := range snapshot.Applications {
+		app, registrations, err := a.unmarshal()
+		if err != nil {
+			return nil, err
+		}
+
+		created, err := restoreApplication(apphubClient, app, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			report.Created = append(report.Created, app.GetName())
+		} else {
+			report.Existing = append(report.Existing, app.GetName())
+		}
+
+		for _, r := range registrations {
+			report.Members = append(report.Members, restoreRegistration(apphubClient, snapshot.ManagementProject, r, dryRun))
+		}
+	}
+	return report, nil
+}
+
+// restoreApplication creates an application from its snapshot unless it
+// exists. It returns true if the application is created.
+func restoreApplication(apiclient appHubClient, app *apphubpb.Application, dryRun bool) (bool, error) {
+	ctx := context.Background()
+	logger := clilog.GetLogger()
+
+	_, err := apiclient.GetApplication(ctx, &apphubpb.GetApplicationRequest{Name: app.GetName()})
+	if err == nil {
+		logger.Info("Application already exists", "application", app.GetName())
+		return false, nil
+	}
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.NotFound {
+		return false, fmt.Errorf("failed to get application %s: %w", app.GetName(), err)
+	}
+	if dryRun {
+		return true, nil
+	}
+
+	req := &apphubpb.CreateApplicationRequest{
+		Parent:        fmt.Sprintf("projects/%s/locations/%s", getNameSegment(app.GetName(), "projects"), getNameSegment(app.GetName(), "locations")),
+		ApplicationId: getNameSegment(app.GetName(), "applications"),
+		Application: &apphubpb.Application{
+			DisplayName: app.GetDisplayName(),
+			Description: app.GetDescription(),
+			Attributes:  app.GetAttributes(),
+			Scope:       &apphubpb.Scope{Type: app.GetScope().GetType()},
+		},
+	}
+	op, err := apiclient.CreateApplication(ctx, req)
+	if err != nil {
+		return false, fmt.Errorf("failed to start application creation: %w", err)
+	}
+	restored, err := op.Wait(ctx)
+	if err != nil {
+		return false, fmt.Errorf("application creation failed during wait: %w", err)
+	}
+	recordMutation("CREATE_APPLICATION", restored.GetName(), nil, restored)
+	logger.Info("Application restored", "application", app.GetName())
+	return true, nil
+}
+
+// restoreRegistration registers a service or workload of a snapshot again
+func restoreRegistration(apiclient appHubClient, projectID string, r *registration, dryRun bool) RestoreResult {
+	result := RestoreResult{ApplicationMember: r.member(), Application: r.appName}
+
+	discoveredName, found, err := resolveDiscoveredName(apiclient, projectID, result.ApplicationMember)
+	switch {
+	case err != nil:
+		result.Status, result.Error = "FAILED", err.Error()
+		return result
+	case !found:
+		result.Status = "MISSING"
+		return result
+	case dryRun:
+		result.Status = "RESTORED"
+		return result
+	}
+
+	// the resource may have been discovered again under a new name
+	restored := &registration{appName: r.appName}
+	if r.service != nil {
+		restored.service = proto.Clone(r.service).(*apphubpb.Service)
+		restored.service.DiscoveredService = discoveredName
+	} else {
+		restored.workload = proto.Clone(r.workload).(*apphubpb.Workload)
+		restored.workload.DiscoveredWorkload = discoveredName
+	}
+
+	if _, err = createRegistration(apiclient, restored, r.appName); err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.AlreadyExists {
+			result.Status = "RESTORED"
+			return result
+		}
+		if st, ok := status.FromError(err); ok && st.Code() == codes.FailedPrecondition {
+			result.Status, result.Error = "REGISTERED", "registered in another application"
+			return result
+		}
+		result.Status, result.Error = "FAILED", err.Error()
+		return result
+	}
+	result.Status = "RESTORED"
+	return result
+}
+
+// resolveDiscoveredName looks up the discovered service or workload of a
+// member by its resource URI. found is false if the resource no longer exists.
+func resolveDiscoveredName(apiclient appHubClient, projectID string, m ApplicationMember) (string, bool, error) {
+	location := getNameSegment(m.DiscoveredName, "locations")
+	name, err := lookupDiscoveredName(apiclient, projectID, location, m.URI, m.AppHubType)
+	if err == nil {
+		return name, true, nil
+	}
+	if isNotDiscovered(err) {
+		return "", false, nil
+	}
+	return "", false, err
+}
@@ -0,0 +1,229 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	app := &apphubpb.Application{
+		Name:        "projects/p/locations/us-west1/applications/shop",
+		DisplayName: "Shop",
+		Description: MANAGED_MARKER,
+		Attributes:  &apphubpb.Attributes{Criticality: &apphubpb.Criticality{Type: apphubpb.Criticality_HIGH}},
+		Scope:       &apphubpb.Scope{Type: apphubpb.Scope_REGIONAL},
+	}
+	registrations := []*registration{
+		{appName: app.Name, service: &apphubpb.Service{
+			Name:              app.Name + "/services/checkout",
+			DiscoveredService: "projects/p/locations/us-west1/discoveredServices/ds-1",
+			ServiceReference:  &apphubpb.ServiceReference{Uri: "//run.googleapis.com/projects/p/locations/us-west1/services/checkout"},
+		}},
+		{appName: app.Name, workload: &apphubpb.Workload{
+			Name:               app.Name + "/workloads/batch",
+			DiscoveredWorkload: "projects/p/locations/us-west1/discoveredWorkloads/dw-1",
+		}},
+	}
+
+	a, err := newSnapshotApplication(app, registrations)
+	if err != nil {
+		t.Fatalf("newSnapshotApplication() error = %v", err)
+	}
+	data, err := json.Marshal(Snapshot{ManagementProject: "p", Applications: []SnapshotApplication{a}})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	snapshot, err := ReadSnapshot(path)
+	if err != nil {
+		t.Fatalf("ReadSnapshot() error = %v", err)
+	}
+	if snapshot.ManagementProject != "p" || len(snapshot.Applications) != 1 {
+		t.Fatalf("ReadSnapshot() = %+v", snapshot)
+	}
+	gotApp, gotRegistrations, err := snapshot.Applications[0].unmarshal()
+	if err != nil {
+		t.Fatalf("unmarshal() error = %v", err)
+	}
+	if gotApp.GetDisplayName() != "Shop" || gotApp.GetAttributes().GetCriticality().GetType() != apphubpb.Criticality_HIGH {
+		t.Errorf("unmarshal() application = %v", gotApp)
+	}
+	if len(gotRegistrations) != 2 {
+		t.Fatalf("unmarshal() registrations = %v", gotRegistrations)
+	}
+	if m := gotRegistrations[0].member(); m.ID != "checkout" || m.URI != registrations[0].service.ServiceReference.Uri {
+		t.Errorf("unmarshal() service = %+v", m)
+	}
+	if m := gotRegistrations[1].member(); m.AppHubType != "discoveredWorkload" || m.DiscoveredName != registrations[1].workload.DiscoveredWorkload {
+		t.Errorf("unmarshal() workload = %+v", m)
+	}
+}
+
+func TestRestoreApplicationDryRun(t *testing.T) {
+	app := &apphubpb.Application{Name: "projects/p/locations/us-west1/applications/shop"}
+
+	exists := &mockAppHubClient{
+		getApplicationFunc: func(ctx context.Context, req *apphubpb.GetApplicationRequest, opts ...gax.CallOption) (*apphubpb.Application, error) {
+			return app, nil
+		},
+	}
+	if created, err := restoreApplication(exists, app, true); err != nil || created {
+		t.Errorf("restoreApplication() = %v, %v, want an existing application", created, err)
+	}
+
+	deleted := &mockAppHubClient{
+		getApplicationFunc: func(ctx context.Context, req *apphubpb.GetApplicationRequest, opts ...gax.CallOption) (*apphubpb.Application, error) {
+			return nil, status.Error(codes.NotFound, "not found")
+		},
+	}
+	if created, err := restoreApplication(deleted, app, true); err != nil || !created {
+		t.Errorf("restoreApplication() = %v, %v, want the application to be created", created, err)
+	}
+}
+
+func TestRestoreRegistrationDryRun(t *testing.T) {
+	r := &registration{
+		appName: "projects/p/locations/us-west1/applications/shop",
+		service: &apphubpb.Service{
+			Name:              "projects/p/locations/us-west1/applications/shop/services/checkout",
+			DiscoveredService: "projects/p/locations/us-west1/discoveredServices/ds-1",
+			ServiceReference:  &apphubpb.ServiceReference{Uri: "//run.googleapis.com/projects/p/locations/us-west1/services/checkout"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		lookup     func(ctx context.Context, req *apphubpb.LookupDiscoveredServiceRequest, opts ...gax.CallOption) (*apphubpb.LookupDiscoveredServiceResponse, error)
+		wantStatus string
+	}{
+		{
+			name: "Resource exists",
+			lookup: func(ctx context.Context, req *apphubpb.LookupDiscoveredServiceRequest, opts ...gax.CallOption) (*apphubpb.LookupDiscoveredServiceResponse, error) {
+				if req.GetParent() != "projects/p/locations/us-west1" {
+					t.Errorf("LookupDiscoveredService() parent = %s", req.GetParent())
+				}
+				return &apphubpb.LookupDiscoveredServiceResponse{
+					DiscoveredService: &apphubpb.DiscoveredService{Name: "projects/p/locations/us-west1/discoveredServices/ds-2"},
+				}, nil
+			},
+			wantStatus: "RESTORED",
+		},
+		{
+			name: "Resource deleted",
+			lookup: func(ctx context.Context, req *apphubpb.LookupDiscoveredServiceRequest, opts ...gax.CallOption) (*apphubpb.LookupDiscoveredServiceResponse, error) {
+				return &apphubpb.LookupDiscoveredServiceResponse{}, nil
+			},
+			wantStatus: "MISSING",
+		},
+		{
+			name: "Resource not found",
+			lookup: func(ctx context.Context, req *apphubpb.LookupDiscoveredServiceRequest, opts ...gax.CallOption) (*apphubpb.LookupDiscoveredServiceResponse, error) {
+				return nil, status.Error(codes.NotFound, "not found")
+			},
+			wantStatus: "MISSING",
+		},
+		{
+			name: "Permission denied",
+			lookup: func(ctx context.Context, req *apphubpb.LookupDiscoveredServiceRequest, opts ...gax.CallOption) (*apphubpb.LookupDiscoveredServiceResponse, error) {
+				return nil, status.Error(codes.PermissionDenied, "permission denied")
+			},
+			wantStatus: "FAILED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &mockAppHubClient{lookupDiscoveredServiceFunc: tt.lookup}
+			got := restoreRegistration(mockClient, "p", r, true)
+			if got.Status != tt.wantStatus {
+				t.Errorf("restoreRegistration() status = %s, want %s (error %s)", got.Status, tt.wantStatus, got.Error)
+			}
+			if got.ID != "checkout" || got.Application != r.appName {
+				t.Errorf("restoreRegistration() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestResolveDiscoveredName(t *testing.T) {
+	member := ApplicationMember{
+		AppHubType:     "discoveredService",
+		DiscoveredName: "projects/p/locations/us-west1/discoveredServices/ds-1",
+		URI:            "//run.googleapis.com/projects/p/locations/us-west1/services/checkout",
+	}
+	tests := []struct {
+		name      string
+		response  *apphubpb.LookupDiscoveredServiceResponse
+		err       error
+		wantFound bool
+		wantErr   bool
+	}{
+		{
+			name:     "Lookup without a result",
+			response: &apphubpb.LookupDiscoveredServiceResponse{},
+		},
+		{
+			name: "Not found",
+			err:  status.Error(codes.NotFound, "not found"),
+		},
+		{
+			name:    "Transport failure",
+			err:     fmt.Errorf("connection reset: %w", io.ErrUnexpectedEOF),
+			wantErr: true,
+		},
+		{
+			name:    "Permission denied",
+			err:     status.Error(codes.PermissionDenied, "denied"),
+			wantErr: true,
+		},
+		{
+			name: "Found",
+			response: &apphubpb.LookupDiscoveredServiceResponse{
+				DiscoveredService: &apphubpb.DiscoveredService{Name: member.DiscoveredName},
+			},
+			wantFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &mockAppHubClient{
+				lookupDiscoveredServiceFunc: func(ctx context.Context, req *apphubpb.LookupDiscoveredServiceRequest, opts ...gax.CallOption) (*apphubpb.LookupDiscoveredServiceResponse, error) {
+					return tt.response, tt.err
+				},
+			}
+			_, found, err := resolveDiscoveredName(mockClient, "p", member)
+			if found != tt.wantFound || (err != nil) != tt.wantErr {
+				t.Errorf("resolveDiscoveredName() found = %v, error = %v", found, err)
+			}
+		})
+	}
+}
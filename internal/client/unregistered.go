@@ -0,0 +1,253 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"internal/clilog"
+	"slices"
+
+	apphubpb "cloud.google.com/go/apphub/apiv1/apphubpb"
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+	"google.golang.org/api/iterator"
+)
+
+var (
+	listRegisteredFunc = listRegisteredResources
+	listDiscoveredFunc = listDiscovered
+)
+
+// UnregisteredResource is a discovered service or workload that is not
+// registered in any application
+type UnregisteredResource struct {
+	Name       string            `json:"name"`
+	AppHubType string            `json:"appHubType"`
+	URI        string            `json:"uri"`
+	Project    string            `json:"project"`
+	Location   string            `json:"location"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
+
+// ListUnregistered returns the discovered services and workloads in the
+// management project that do not belong to any application. Labels of the
+// underlying resources are read from CAIS.
+func ListUnregistered(managementProject string, locations []string) ([]UnregisteredResource, error) {
+	apphubClient, err := getAppHubClientFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error getting apphub client: %w", err)
+	}
+	defer closeAppHubClient(apphubClient)
+
+	registered, err := listRegistered(apphubClient, managementProject, locations)
+	if err != nil {
+		return nil, err
+	}
+
+	var unregistered []UnregisteredResource
+	for _, location := range locations {
+		discovered, err := listDiscoveredFunc(apphubClient, managementProject, location)
+		if err != nil {
+			return nil, err
+		}
+		unregistered = append(unregistered, filterUnregistered(discovered, registered)...)
+	}
+
+	if err = joinAssetLabels(unregistered, locations); err != nil {
+		return nil, err
+	}
+	return unregistered, nil
+}
+
+// listRegistered returns the discovered services and workloads registered in
+// any application of the locations or global, by discovered name. Generate
+// registers regional resources in global applications when it runs on several
+// locations.
+func listRegistered(apiclient appHubClient, projectID string, locations []string) (map[string]UnregisteredResource, error) {
+	logger := clilog.GetLogger()
+	registered := make(map[string]UnregisteredResource)
+
+	registrationLocations := slices.Clone(locations)
+	if !slices.Contains(registrationLocations, "global") {
+		registrationLocations = append(registrationLocations, "global")
+	}
+	for _, location := range registrationLocations {
+		resources, err := listRegisteredFunc(apiclient, projectID, location)
+		if err != nil {
+			return nil, err
+		}
+		for name, resource := range resources {
+			registered[name] = resource
+		}
+	}
+	logger.Info("Found registered resources", "locations", registrationLocations, "registered", len(registered))
+	return registered, nil
+}
+
+// listRegisteredResources returns the services and workloads registered in
+// any application of a location, by discovered name. The discovered lists of
+// App Hub only return the resources that are not registered yet.
+func listRegisteredResources(apiclient appHubClient, projectID, location string) (map[string]UnregisteredResource, error) {
+	ctx := context.Background()
+	registered := make(map[string]UnregisteredResource)
+
+	listApplications := apiclient.ListApplications(ctx, &apphubpb.ListApplicationsRequest{
+		Parent: fmt.Sprintf("projects/%s/locations/%s", projectID, location),
+	})
+	for {
+		app, err := listApplications.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list applications: %w", err)
+		}
+
+		listServices := apiclient.ListServices(ctx, &apphubpb.ListServicesRequest{Parent: app.GetName()})
+		for {
+			service, err := listServices.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to list services: %w", err)
+			}
+			registered[service.GetDiscoveredService()] = UnregisteredResource{
+				Name:       service.GetDiscoveredService(),
+				AppHubType: "discoveredService",
+				URI:        service.GetServiceReference().GetUri(),
+				Project:    service.GetServiceProperties().GetGcpProject(),
+				Location:   service.GetServiceProperties().GetLocation(),
+			}
+		}
+
+		listWorkloads := apiclient.ListWorkloads(ctx, &apphubpb.ListWorkloadsRequest{Parent: app.GetName()})
+		for {
+			workload, err := listWorkloads.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to list workloads: %w", err)
+			}
+			registered[workload.GetDiscoveredWorkload()] = UnregisteredResource{
+				Name:       workload.GetDiscoveredWorkload(),
+				AppHubType: "discoveredWorkload",
+				URI:        workload.GetWorkloadReference().GetUri(),
+				Project:    workload.GetWorkloadProperties().GetGcpProject(),
+				Location:   workload.GetWorkloadProperties().GetLocation(),
+			}
+		}
+	}
+	return registered, nil
+}
+
+// listDiscovered pages through the discovered services and workloads of a location
+func listDiscovered(apiclient appHubClient, projectID, location string) ([]UnregisteredResource, error) {
+	ctx := context.Background()
+	parent := fmt.Sprintf("projects/%s/locations/%s", projectID, location)
+	var discovered []UnregisteredResource
+
+	listServices := apiclient.ListDiscoveredServices(ctx, &apphubpb.ListDiscoveredServicesRequest{Parent: parent})
+	for {
+		service, err := listServices.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list discovered services: %w", err)
+		}
+		discovered = append(discovered, UnregisteredResource{
+			Name:       service.GetName(),
+			AppHubType: "discoveredService",
+			URI:        service.GetServiceReference().GetUri(),
+			Project:    service.GetServiceProperties().GetGcpProject(),
+			Location:   service.GetServiceProperties().GetLocation(),
+		})
+	}
+
+	listWorkloads := apiclient.ListDiscoveredWorkloads(ctx, &apphubpb.ListDiscoveredWorkloadsRequest{Parent: parent})
+	for {
+		workload, err := listWorkloads.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list discovered workloads: %w", err)
+		}
+		discovered = append(discovered, UnregisteredResource{
+			Name:       workload.GetName(),
+			AppHubType: "discoveredWorkload",
+			URI:        workload.GetWorkloadReference().GetUri(),
+			Project:    workload.GetWorkloadProperties().GetGcpProject(),
+			Location:   workload.GetWorkloadProperties().GetLocation(),
+		})
+	}
+	return discovered, nil
+}
+
+// filterUnregistered removes the discovered resources that are registered
+func filterUnregistered(discovered []UnregisteredResource, registered map[string]UnregisteredResource) []UnregisteredResource {
+	var unregistered []UnregisteredResource
+	for _, d := range discovered {
+		if _, ok := registered[d.Name]; !ok {
+			unregistered = append(unregistered, d)
+		}
+	}
+	return unregistered
+}
+
+// joinAssetLabels sets the labels of each resource from its CAIS asset
+func joinAssetLabels(resources []UnregisteredResource, locations []string) error {
+	var projects []string
+	for _, r := range resources {
+		projects = append(projects, r.Project)
+	}
+
+	assets, err := searchAssetsByURI(projects, locations)
+	if err != nil {
+		return err
+	}
+	for i := range resources {
+		if asset, ok := assets[resources[i].URI]; ok {
+			resources[i].Labels = asset.GetLabels()
+		}
+	}
+	return nil
+}
+
+// searchAssetsByURI searches CAIS once per project and indexes the assets by
+// their CAIS name and by the URI the asset type registry maps them to, since
+// some App Hub URIs differ from the CAIS name.
+func searchAssetsByURI(projects, locations []string) (map[string]*assetpb.ResourceSearchResult, error) {
+	var searched []string
+	assets := make(map[string]*assetpb.ResourceSearchResult)
+	for _, project := range projects {
+		if project == "" || slices.Contains(searched, project) {
+			continue
+		}
+		searched = append(searched, project)
+
+		projectAssets, err := searchAssetsFunc(project, "", "", "", "", "", locations, getSupportedAssetTypes())
+		if err != nil {
+			return nil, fmt.Errorf("error searching assets: %w", err)
+		}
+		for _, asset := range projectAssets {
+			assets[asset.GetName()] = asset
+			assets[fixResourceURI(asset.GetName(), asset)] = asset
+		}
+	}
+	return assets, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"slices"
+	"testing"
+
+	assetpb "cloud.google.com/go/asset/apiv1/assetpb"
+)
+
+func TestFilterUnregistered(t *testing.T) {
+	discovered := []UnregisteredResource{
+		{Name: "projects/p/locations/us-west1/discoveredServices/a"},
+		{Name: "projects/p/locations/us-west1/discoveredServices/b"},
+		{Name: "projects/p/locations/us-west1/discoveredWorkloads/c"},
+	}
+	registered := map[string]UnregisteredResource{"projects/p/locations/us-west1/discoveredServices/b": discovered[1]}
+
+	got := filterUnregistered(discovered, registered)
+	if len(got) != 2 || got[0].Name != discovered[0].Name || got[1].Name != discovered[2].Name {
+		t.Errorf("filterUnregistered() = %v", got)
+	}
+}
+
+func TestListUnregisteredInGlobalApplication(t *testing.T) {
+	const checkout = "projects/p/locations/us-west1/discoveredServices/checkout"
+	var registrationLocations []string
+	listRegisteredFunc = func(apiclient appHubClient, projectID, location string) (map[string]UnregisteredResource, error) {
+		registrationLocations = append(registrationLocations, location)
+		if location == "global" {
+			return map[string]UnregisteredResource{checkout: {Name: checkout}}, nil
+		}
+		return map[string]UnregisteredResource{}, nil
+	}
+	listDiscoveredFunc = func(apiclient appHubClient, projectID, location string) ([]UnregisteredResource, error) {
+		return []UnregisteredResource{
+			{Name: checkout, AppHubType: "discoveredService", Location: location},
+			{Name: "projects/p/locations/us-west1/discoveredServices/cart", AppHubType: "discoveredService", Location: location},
+		}, nil
+	}
+	getAppHubClientFunc = func() (appHubClient, error) {
+		return &mockAppHubClient{}, nil
+	}
+	searchAssetsFunc = func(parent, labelKey, labelValue, tagKey, tagValue, contains string, locations, assetTypes []string) ([]*assetpb.ResourceSearchResult, error) {
+		return nil, nil
+	}
+	defer func() {
+		listRegisteredFunc = listRegisteredResources
+		listDiscoveredFunc = listDiscovered
+		getAppHubClientFunc = getAppHubClient
+		searchAssetsFunc = searchAssets
+	}()
+
+	got, err := ListUnregistered("p", []string{"us-west1"})
+	if err != nil {
+		t.Fatalf("ListUnregistered() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "projects/p/locations/us-west1/discoveredServices/cart" {
+		t.Errorf("ListUnregistered() = %v, want only the resource not registered in the global application", got)
+	}
+	if !slices.Equal(registrationLocations, []string{"us-west1", "global"}) {
+		t.Errorf("ListUnregistered() read registrations of %v, want us-west1 and global", registrationLocations)
+	}
+}
+
+func TestJoinAssetLabels(t *testing.T) {
+	var scopes []string
+	searchAssetsFunc = func(parent, labelKey, labelValue, tagKey, tagValue, contains string, locations, assetTypes []string) ([]*assetpb.ResourceSearchResult, error) {
+		scopes = append(scopes, parent)
+		return []*assetpb.ResourceSearchResult{
+			{
+				Name:      "//run.googleapis.com/projects/p1/locations/us-west1/services/checkout",
+				AssetType: "run.googleapis.com/Service",
+				Project:   "projects/111",
+				Labels:    map[string]string{"appid": "shop"},
+			},
+			{
+				Name:      "//cloudsql.googleapis.com/projects/p1/instances/pay-db",
+				AssetType: "sqladmin.googleapis.com/Instance",
+				Project:   "projects/111",
+				Labels:    map[string]string{"appid": "payments"},
+			},
+		}, nil
+	}
+	defer func() { searchAssetsFunc = searchAssets }()
+
+	resources := []UnregisteredResource{
+		{URI: "//run.googleapis.com/projects/p1/locations/us-west1/services/checkout", Project: "projects/p1"},
+		{URI: "//sqladmin.googleapis.com/projects/111/instances/pay-db", Project: "projects/p1"},
+		{URI: "//storage.googleapis.com/bucket", Project: "projects/p1"},
+	}
+	if err := joinAssetLabels(resources, []string{"us-west1"}); err != nil {
+		t.Fatalf("joinAssetLabels() error = %v", err)
+	}
+
+	if len(scopes) != 1 || scopes[0] != "projects/p1" {
+		t.Errorf("joinAssetLabels() searched %v, want each project once", scopes)
+	}
+	if resources[0].Labels["appid"] != "shop" {
+		t.Errorf("Labels = %v, want appid=shop", resources[0].Labels)
+	}
+	if resources[1].Labels["appid"] != "payments" {
+		t.Errorf("Labels = %v, want appid=payments", resources[1].Labels)
+	}
+	if resources[2].Labels != nil {
+		t.Errorf("Labels = %v, want none", resources[2].Labels)
+	}
+}
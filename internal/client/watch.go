@@ -0,0 +1,314 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"internal/clilog"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DEFAULT_WATCH_INTERVAL is the time between two iterations of the watch jobs
+const DEFAULT_WATCH_INTERVAL = 10 * time.Minute
+
+// DEFAULT_RESYNC_INTERVAL is the time after which the state kept between
+// iterations is dropped and every asset is looked up again
+const DEFAULT_RESYNC_INTERVAL = 6 * time.Hour
+
+// WatchConfig is the top level structure of a watch file
+type WatchConfig struct {
+	Interval       time.Duration  `yaml:"interval,omitempty"`
+	ResyncInterval time.Duration  `yaml:"resyncInterval,omitempty"`
+	Jobs           []*GenerateJob `yaml:"jobs"`
+}
+
+// JobStatus is the outcome of a watch job in an iteration
+type JobStatus struct {
+	Name         string `json:"name"`
+	Applications int    `json:"applications"`
+	Members      int    `json:"members"`
+	// Changes is the number of App Hub mutations the job made
+	Changes int    `json:"changes"`
+	Error   string `json:"error,omitempty"`
+}
+
+// WatchStatus is the status of the last watch iteration
+type WatchStatus struct {
+	Iteration   int         `json:"iteration"`
+	RunID       string      `json:"runId,omitempty"`
+	Started     string      `json:"started,omitempty"`
+	Finished    string      `json:"finished,omitempty"`
+	Duration    string      `json:"duration,omitempty"`
+	LastSuccess string      `json:"lastSuccess,omitempty"`
+	Healthy     bool        `json:"healthy"`
+	Jobs        []JobStatus `json:"jobs"`
+}
+
+// ParseWatchConfig reads a watch file, validates its jobs and reads the rules
+// and attributes files they reference
+func ParseWatchConfig(data []byte) (*WatchConfig, error) {
+	config := &WatchConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse watch file: %w", err)
+	}
+
+	if config.Interval == 0 {
+		config.Interval = DEFAULT_WATCH_INTERVAL
+	}
+	if config.Interval < time.Minute {
+		return nil, fmt.Errorf("interval must be at least 1m")
+	}
+	if config.ResyncInterval == 0 {
+		config.ResyncInterval = DEFAULT_RESYNC_INTERVAL
+	}
+	if len(config.Jobs) == 0 {
+		return nil, fmt.Errorf("at least one job is required")
+	}
+
+	names := make(map[string]bool)
+	for i, job := range config.Jobs {
+		if job.Name == "" {
+			return nil, fmt.Errorf("job %d: name is required", i+1)
+		}
+		if names[job.Name] {
+			return nil, fmt.Errorf("job %s: duplicate name", job.Name)
+		}
+		names[job.Name] = true
+		if err := job.Validate(); err != nil {
+			return nil, fmt.Errorf("job %s: %w", job.Name, err)
+		}
+	}
+	return config, nil
+}
+
+// reconcileState remembers, between watch iterations, the discovered names
+// of assets, the applications that exist and the registrations made, so that
+// an iteration only calls App Hub for what changed. A nil state remembers
+// nothing, which is the behavior of a single generate run.
+type reconcileState struct {
+	mu           sync.Mutex
+	discovered   map[string]string
+	applications map[string]bool
+	registered   map[string]string
+}
+
+var watchState *reconcileState
+
+func newReconcileState() *reconcileState {
+	return &reconcileState{
+		discovered:   make(map[string]string),
+		applications: make(map[string]bool),
+		registered:   make(map[string]string),
+	}
+}
+
+func (s *reconcileState) getDiscoveredName(location, assetName string) string {
+	if s == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.discovered[location+"|"+assetName]
+}
+
+func (s *reconcileState) setDiscoveredName(location, assetName, discoveredName string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.discovered[location+"|"+assetName] = discoveredName
+}
+
+func (s *reconcileState) hasApplication(appName string) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.applications[appName]
+}
+
+func (s *reconcileState) addApplication(appName string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.applications[appName] = true
+}
+
+func (s *reconcileState) isRegistered(discoveredName, appName string) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.registered[discoveredName] == appName
+}
+
+func (s *reconcileState) setRegistered(discoveredName, appName string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.registered[discoveredName] = appName
+}
+
+// Watcher runs the jobs of a watch file periodically
+type Watcher struct {
+	config   *WatchConfig
+	newRunID func() (string, error)
+	journal  string
+
+	mu          sync.Mutex
+	status      WatchStatus
+	started     time.Time
+	lastSuccess time.Time
+	lastResync  time.Time
+}
+
+// NewWatcher returns a watcher for the jobs of config. Each iteration records
+// its mutations in the journal with a run ID returned by newRunID.
+func NewWatcher(config *WatchConfig, journal string, newRunID func() (string, error)) *Watcher {
+	return &Watcher{config: config, journal: journal, newRunID: newRunID}
+}
+
+// Run runs an iteration immediately and then every interval until ctx is
+// done. An iteration in progress finishes its current job before Run returns.
+func (w *Watcher) Run(ctx context.Context) {
+	logger := clilog.GetLogger()
+
+	w.mu.Lock()
+	w.started = time.Now()
+	w.mu.Unlock()
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.RunIteration(ctx)
+		select {
+		case <-ctx.Done():
+			logger.Info("Watch stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunIteration runs every job once. A failed job does not stop the others;
+// the state kept between iterations is dropped so the next iteration starts
+// from App Hub again. Excluded assets are kept for the current iteration only.
+func (w *Watcher) RunIteration(ctx context.Context) {
+	logger := clilog.GetLogger()
+	start := time.Now()
+	resetExcludedAssets()
+
+	if watchState == nil || start.Sub(w.lastResync) >= w.config.ResyncInterval {
+		logger.Info("Starting a full resync")
+		watchState = newReconcileState()
+		w.lastResync = start
+	}
+
+	runID, err := w.newRunID()
+	if err != nil {
+		logger.Warn("Failed to generate run ID", "error", err)
+	}
+	SetJournal(w.journal, runID)
+
+	w.mu.Lock()
+	status := WatchStatus{Iteration: w.status.Iteration + 1, RunID: runID, Started: start.UTC().Format(time.RFC3339)}
+	w.status.Iteration, w.status.RunID, w.status.Started = status.Iteration, status.RunID, status.Started
+	w.mu.Unlock()
+
+	failed := false
+	for _, job := range w.config.Jobs {
+		if ctx.Err() != nil {
+			logger.Info("Watch stopping, skipping the remaining jobs")
+			failed = true
+			break
+		}
+		jobStatus := w.runJob(job)
+		if jobStatus.Error != "" {
+			failed = true
+		}
+		status.Jobs = append(status.Jobs, jobStatus)
+	}
+	if failed {
+		watchState = nil
+	}
+
+	finished := time.Now()
+	status.Finished = finished.UTC().Format(time.RFC3339)
+	status.Duration = finished.Sub(start).Round(time.Second).String()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !failed {
+		w.lastSuccess = finished
+	}
+	if !w.lastSuccess.IsZero() {
+		status.LastSuccess = w.lastSuccess.UTC().Format(time.RFC3339)
+	}
+	w.status = status
+	logger.Info("Watch iteration finished", "iteration", status.Iteration, "runId", runID, "duration", status.Duration)
+}
+
+func (w *Watcher) runJob(job *GenerateJob) JobStatus {
+	logger := clilog.GetLogger()
+	jobStatus := JobStatus{Name: job.Name}
+
+	logger.Info("Running watch job", "job", job.Name, "mode", job.Mode())
+	SetProvenance("", job.Mode())
+	before := GetJournaledMutations()
+
+	generated, _, err := job.Generate(false)
+	jobStatus.Changes = GetJournaledMutations() - before
+	jobStatus.Applications = len(generated)
+	for _, members := range generated {
+		// every member is stored as its discovered id, type and asset name
+		jobStatus.Members += len(members) / 3
+	}
+	if err != nil {
+		logger.Error("Watch job failed", "job", job.Name, "error", err)
+		jobStatus.Error = err.Error()
+	}
+	return jobStatus
+}
+
+// Status returns the status of the last iteration
+func (w *Watcher) Status() WatchStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	status := w.status
+	status.Healthy = w.healthy(time.Now())
+	return status
+}
+
+// healthy is false when no iteration succeeded for three intervals, which
+// means every iteration failed or one is stuck
+func (w *Watcher) healthy(now time.Time) bool {
+	last := w.lastSuccess
+	if last.IsZero() {
+		last = w.started
+	}
+	return now.Sub(last) < 3*w.config.Interval
+}
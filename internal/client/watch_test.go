@@ -0,0 +1,156 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWatchConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "Valid",
+			data: `
+interval: 5m
+jobs:
+- name: labels
+  parent: projects/my-project
+  locations: [us-central1]
+  labelKey: app
+`,
+		},
+		{
+			name:    "No jobs",
+			data:    "interval: 5m\n",
+			wantErr: "at least one job is required",
+		},
+		{
+			name:    "Interval too short",
+			data:    "interval: 30s\njobs:\n- name: a\n",
+			wantErr: "interval must be at least 1m",
+		},
+		{
+			name: "Duplicate name",
+			data: `
+jobs:
+- {name: a, parent: projects/p, locations: [us-west1], autoDetect: true}
+- {name: a, parent: projects/p, locations: [us-west1], autoDetect: true}
+`,
+			wantErr: "job a: duplicate name",
+		},
+		{
+			name:    "Two modes",
+			data:    "jobs:\n- {name: a, parent: projects/p, locations: [us-west1], autoDetect: true, labelKey: app}\n",
+			wantErr: "exactly one generate mode is required, found 2",
+		},
+		{
+			name:    "Folder without management project",
+			data:    "jobs:\n- {name: a, parent: folders/123, locations: [us-west1], autoDetect: true}\n",
+			wantErr: "managementProject is required when parent is a folder",
+		},
+		{
+			name:    "Project keys without app name",
+			data:    "jobs:\n- {name: a, parent: projects/p, locations: [us-west1], projectKeys: [p]}\n",
+			wantErr: "appName is required with projectKeys",
+		},
+		{
+			name:    "Namespace grouping without per namespace mode",
+			data:    "jobs:\n- {name: a, parent: projects/p, locations: [us-west1], autoDetect: true, namespaceGroupBy: namespace}\n",
+			wantErr: "namespaceGroupBy is only supported with perK8sNamespace",
+		},
+		{
+			name:    "Kubernetes labels without per app label mode",
+			data:    "jobs:\n- {name: a, parent: projects/p, locations: [us-west1], perK8sNamespace: true, k8sAppLabels: [app]}\n",
+			wantErr: "k8sAppLabels is only supported with perK8sAppLabel",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseWatchConfig([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseWatchConfig() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseWatchConfig() error = %v", err)
+			}
+			if config.Interval != 5*time.Minute || config.ResyncInterval != DEFAULT_RESYNC_INTERVAL {
+				t.Errorf("ParseWatchConfig() intervals = %s, %s", config.Interval, config.ResyncInterval)
+			}
+			job := config.Jobs[0]
+			if job.ManagementProject != "my-project" || job.Mode() != "label-key" {
+				t.Errorf("ParseWatchConfig() job = %+v, mode %s", job, job.Mode())
+			}
+		})
+	}
+}
+
+func TestReconcileState(t *testing.T) {
+	var nilState *reconcileState
+	nilState.setDiscoveredName("us-west1", "//run/svc", "discovered/1")
+	if nilState.getDiscoveredName("us-west1", "//run/svc") != "" || nilState.hasApplication("apps/a") {
+		t.Errorf("nil state remembered a value")
+	}
+
+	state := newReconcileState()
+	state.setDiscoveredName("us-west1", "//run/svc", "discovered/1")
+	if got := state.getDiscoveredName("us-west1", "//run/svc"); got != "discovered/1" {
+		t.Errorf("getDiscoveredName() = %s", got)
+	}
+	if got := state.getDiscoveredName("us-east1", "//run/svc"); got != "" {
+		t.Errorf("getDiscoveredName() in another region = %s", got)
+	}
+	state.setRegistered("discovered/1", "apps/a")
+	if !state.isRegistered("discovered/1", "apps/a") || state.isRegistered("discovered/1", "apps/b") {
+		t.Errorf("isRegistered() does not match the registered application")
+	}
+}
+
+func TestWatcherHealthy(t *testing.T) {
+	now := time.Now()
+	w := &Watcher{config: &WatchConfig{Interval: time.Minute}, started: now.Add(-2 * time.Minute)}
+	if !w.healthy(now) {
+		t.Errorf("healthy() = false before the first iteration finished")
+	}
+	w.started = now.Add(-5 * time.Minute)
+	if w.healthy(now) {
+		t.Errorf("healthy() = true without a successful iteration for three intervals")
+	}
+	w.lastSuccess = now.Add(-time.Minute)
+	if !w.healthy(now) {
+		t.Errorf("healthy() = false after a recent successful iteration")
+	}
+}
+
+func TestRunIterationResetsExcludedAssets(t *testing.T) {
+	assetExclusions.excluded = []ExcludedAsset{{Name: "//run.googleapis.com/services/old", Reason: "name old is excluded"}}
+	defer resetExcludedAssets()
+
+	w := NewWatcher(&WatchConfig{Interval: time.Minute}, "", func() (string, error) { return "run", nil })
+	w.RunIteration(context.Background())
+	if excluded := GetExcludedAssets(); len(excluded) != 0 {
+		t.Errorf("GetExcludedAssets() = %v after a new iteration, want none", excluded)
+	}
+}
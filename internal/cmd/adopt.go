@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"internal/client"
+
+	"github.com/spf13/cobra"
+)
+
+// AdoptAppsCmd to mark existing applications as managed by the tool
+var AdoptAppsCmd = &cobra.Command{
+	Use:   "adopt",
+	Short: "Adopt existing App Hub Applications",
+	Long: "Mark App Hub Applications that were not created by this tool as managed, " +
+		"so that generate and delete are allowed to modify them",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		name := GetStringParam(cmd.Flag("name"))
+		all, _ := cmd.Flags().GetBool("all")
+
+		if managementProject == "" {
+			return fmt.Errorf("management project is a required field")
+		}
+		if len(locations) == 0 {
+			return fmt.Errorf("at least one location is required")
+		}
+		if name == "" && !all {
+			return fmt.Errorf("either name or all is required")
+		}
+		if name != "" && all {
+			return fmt.Errorf("name and all cannot be used together")
+		}
+		return
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		cmd.SilenceUsage = true
+
+		name := GetStringParam(cmd.Flag("name"))
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		client.SetProvenance(cmd.Root().Version, "adopt")
+
+		adopted, err := client.AdoptApps(managementProject, name, locations, dryRun)
+		for _, appName := range adopted {
+			if dryRun {
+				fmt.Printf("Would adopt %s\n", appName)
+			} else {
+				fmt.Printf("Adopted %s\n", appName)
+			}
+		}
+		return err
+	},
+	Example: `Adopt an application: ` + adoptAppsCmdExamples[0] + `
+Preview adopting every unmanaged application in a location: ` + adoptAppsCmdExamples[1],
+}
+
+var adoptAppsCmdExamples = []string{
+	`apphub-app-creator apps adopt --name $name --management-project $project --locations us-west1`,
+	`apphub-app-creator apps adopt --all --management-project $project --locations us-west1 --dry-run`,
+}
+
+func GetAdoptAppExample(i int) string {
+	return adoptAppsCmdExamples[i]
+}
+
+func init() {
+	var name string
+	var all, dryRun bool
+
+	AdoptAppsCmd.Flags().StringVarP(&name, "name", "",
+		"", "Name of the App Hub Application to adopt")
+	AdoptAppsCmd.Flags().BoolVarP(&all, "all", "",
+		false, "Adopt every unmanaged application in the locations")
+	AdoptAppsCmd.Flags().BoolVarP(&dryRun, "dry-run", "",
+		false, "List the applications that would be adopted without changing them")
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"internal/client"
+
+	"github.com/spf13/cobra"
+)
+
+// AttachProjectsCmd to attach service projects to the management project
+var AttachProjectsCmd = &cobra.Command{
+	Use:   "attach-projects",
+	Short: "Attach resource projects to the management project as service projects",
+	Long: "Find the projects of the assets under parent, or use the projects given, and attach the ones " +
+		"that are not attached to the management project as service projects. Resources of projects that " +
+		"are not attached cannot be looked up or registered in App Hub",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		projects, _ := cmd.Flags().GetStringArray("projects")
+		if managementProject == "" && parent == "" {
+			return fmt.Errorf("management project is a required field")
+		}
+		if len(projects) == 0 {
+			if parent == "" {
+				return fmt.Errorf("parent or projects is a required field")
+			}
+			if len(locations) == 0 {
+				return fmt.Errorf("at least one location is required")
+			}
+		}
+		return
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		cmd.SilenceUsage = true
+
+		projects, _ := cmd.Flags().GetStringArray("projects")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		if managementProject == "" {
+			managementProject, err = GetProjectID(parent)
+			if err != nil {
+				return err
+			}
+		}
+
+		serviceProjects, err := client.AttachServiceProjects(parent, managementProject, locations, projects, dryRun)
+		if err != nil {
+			return err
+		}
+
+		PrintServiceProjects(serviceProjects, dryRun)
+		return nil
+	},
+	Example: `Report the projects of assets in a folder that are not attached: ` + attachProjectsCmdExamples[0] + `
+Attach projects to the management project: ` + attachProjectsCmdExamples[1],
+}
+
+var attachProjectsCmdExamples = []string{
+	`apphub-app-creator apps attach-projects --parent folders/$folder --management-project $project --locations us-west1 --dry-run`,
+	`apphub-app-creator apps attach-projects --management-project $project --projects $project1 --projects $project2`,
+}
+
+func GetAttachProjectsExample(i int) string {
+	return attachProjectsCmdExamples[i]
+}
+
+func init() {
+	var projects []string
+	var dryRun bool
+
+	AttachProjectsCmd.Flags().StringArrayVarP(&projects, "projects", "",
+		[]string{}, "Project IDs to attach. Defaults to the projects of the assets found under parent in the locations")
+	AttachProjectsCmd.Flags().BoolVarP(&dryRun, "dry-run", "",
+		false, "Report the projects that are not attached without attaching them")
+}
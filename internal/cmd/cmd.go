@@ -15,6 +15,8 @@
 package cmd
 
 import (
+	"internal/client"
+
 	"github.com/spf13/cobra"
 )
 
@@ -27,18 +29,40 @@ var Cmd = &cobra.Command{
 }
 
 var (
-	parent, managementProject string
-	locations, projectKeys    []string
+	parent, managementProject, configFile, journal string
+	locations, projectKeys                         []string
 )
 
 func init() {
 	Cmd.PersistentFlags().StringVarP(&parent, "parent", "",
 		"", "The scope of CAIS Asset Search. Must be of the format projects/{project} or folders/{folder}")
 	Cmd.PersistentFlags().StringArrayVarP(&locations, "locations", "",
-		[]string{}, "GCP location names to filter CAIS Asset Search (e.g. us-central1)")
+		[]string{}, "GCP location names to filter CAIS Asset Search (e.g. us-central1). "+
+			"The delete, list, unregistered and coverage commands accept all to use every App Hub location of the management project")
 	Cmd.PersistentFlags().StringVarP(&managementProject, "management-project", "",
 		"", "App Hub Management Project Id. If parent is set to projects/{project}, then management-project defaults to the same")
+	Cmd.PersistentFlags().StringVarP(&configFile, "config", "",
+		"", "Path to a YAML configuration file containing exclusions and asset type overrides")
+	Cmd.PersistentFlags().StringVarP(&journal, "journal", "",
+		client.DEFAULT_JOURNAL, "Path of the JSONL journal recording every change made to App Hub, used by apps undo. "+
+			"Set to an empty string to disable")
 
 	Cmd.AddCommand(GenAppsCmd)
 	Cmd.AddCommand(DelAppsCmd)
+	Cmd.AddCommand(RestoreAppsCmd)
+	Cmd.AddCommand(UndoCmd)
+	Cmd.AddCommand(ListAppsCmd)
+	Cmd.AddCommand(DescribeAppCmd)
+	Cmd.AddCommand(AdoptAppsCmd)
+	Cmd.AddCommand(DetachCmd)
+	Cmd.AddCommand(MoveCmd)
+	Cmd.AddCommand(MergeAppsCmd)
+	Cmd.AddCommand(SplitAppCmd)
+	Cmd.AddCommand(UnregisteredCmd)
+	Cmd.AddCommand(AttachProjectsCmd)
+	Cmd.AddCommand(DoctorCmd)
+	Cmd.AddCommand(WatchCmd)
+	Cmd.AddCommand(FeedCmd)
+	Cmd.AddCommand(ServeCmd)
+	Cmd.AddCommand(CoverageCmd)
 }
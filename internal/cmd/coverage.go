@@ -0,0 +1,99 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"internal/client"
+
+	"github.com/spf13/cobra"
+)
+
+// CoverageCmd to report how many discovered resources are registered in applications
+var CoverageCmd = &cobra.Command{
+	Use:   "coverage",
+	Short: "Report App Hub onboarding coverage",
+	Long: "Report how many discovered services and workloads are registered in App Hub Applications, " +
+		"per project, location and resource type",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		output := GetStringParam(cmd.Flag("output"))
+		minCoverage, _ := cmd.Flags().GetFloat64("min-coverage")
+
+		if managementProject == "" {
+			return fmt.Errorf("management project is a required field")
+		}
+		if len(locations) == 0 {
+			return fmt.Errorf("at least one location is required")
+		}
+		if output != "table" && output != "json" {
+			return fmt.Errorf("output must be one of table or json")
+		}
+		if minCoverage < 0 || minCoverage > 100 {
+			return fmt.Errorf("min-coverage must be between 0 and 100")
+		}
+		return
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		cmd.SilenceUsage = true
+
+		output := GetStringParam(cmd.Flag("output"))
+		minCoverage, _ := cmd.Flags().GetFloat64("min-coverage")
+
+		appLocations, err := client.ResolveLocations(managementProject, locations)
+		if err != nil {
+			return err
+		}
+
+		report, err := client.GetCoverage(managementProject, appLocations)
+		if err != nil {
+			return err
+		}
+
+		if output == "json" {
+			if err = PrintJSON(report); err != nil {
+				return err
+			}
+		} else {
+			PrintCoverage(report)
+		}
+
+		if cmd.Flags().Changed("min-coverage") && report.Overall.Coverage < minCoverage {
+			return fmt.Errorf("coverage %.2f%% is below the minimum coverage of %.2f%%",
+				report.Overall.Coverage, minCoverage)
+		}
+		return nil
+	},
+	Example: `Report coverage in the following locations: ` + coverageCmdExamples[0] + `
+Fail if less than 80% of discovered resources are registered: ` + coverageCmdExamples[1],
+}
+
+var coverageCmdExamples = []string{
+	`apphub-app-creator apps coverage --management-project $project --locations us-west1 --locations global`,
+	`apphub-app-creator apps coverage --management-project $project --locations us-west1 --output json --min-coverage 80`,
+}
+
+func GetCoverageExample(i int) string {
+	return coverageCmdExamples[i]
+}
+
+func init() {
+	var output string
+	var minCoverage float64
+
+	CoverageCmd.Flags().StringVarP(&output, "output", "",
+		"table", "Output format, one of table or json")
+	CoverageCmd.Flags().Float64VarP(&minCoverage, "min-coverage", "",
+		0, "Exit with an error if the overall coverage percentage is below this threshold")
+}
@@ -15,8 +15,12 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"internal/client"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -39,29 +43,119 @@ var DelAppsCmd = &cobra.Command{
 		cmd.SilenceUsage = true
 
 		name := GetStringParam(cmd.Flag("name"))
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		maxDeletions, _ := cmd.Flags().GetInt("max-deletions")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		includeUnmanaged, _ := cmd.Flags().GetBool("include-unmanaged")
+		snapshot := GetStringParam(cmd.Flag("snapshot"))
 
-		if name != "" {
-			err = client.DeleteApp(
-				managementProject,
-				name,
-				locations)
+		appLocations, err := client.ResolveLocations(managementProject, locations)
+		if err != nil {
+			return err
+		}
 
+		plan, err := client.PlanDeletion(managementProject, name, appLocations, includeUnmanaged)
+		if err != nil {
 			return err
 		}
 
-		err = client.DeleteAllApps(
-			managementProject,
-			locations)
+		if len(plan) == 0 {
+			if name != "" {
+				return fmt.Errorf("application %s not found in locations %v", name, appLocations)
+			}
+			fmt.Println("No applications found to delete")
+			return nil
+		}
+
+		PrintDeletionPlan(plan)
+
+		if dryRun {
+			return nil
+		}
+
+		if maxDeletions > 0 && len(plan) > maxDeletions {
+			return fmt.Errorf("refusing to delete %d applications, more than --max-deletions=%d",
+				len(plan), maxDeletions)
+		}
+
+		if !yes && len(plan) > 1 {
+			if err = confirmDeletion(confirmInput, os.Stdout, managementProject, len(plan)); err != nil {
+				return err
+			}
+		}
+
+		var appNames []string
+		for _, app := range plan {
+			appNames = append(appNames, app.Name)
+		}
+		if err = writeSnapshot(snapshot, appNames); err != nil {
+			return err
+		}
 
+		results, err := client.DeleteApps(managementProject, plan, concurrency, newDeletionProgress(os.Stdout))
+		if err != nil {
+			PrintDeletionFailures(results)
+		}
 		return err
 	},
 	Example: `Delete all applications in the following locations: ` + delAppsCmdExamples[0] + `
-Delete application with name $name in the location: ` + delAppsCmdExamples[1],
+Delete application with name $name in the location: ` + delAppsCmdExamples[1] + `
+Preview the applications, services and workloads that would be deleted: ` + delAppsCmdExamples[2] + `
+Delete without a confirmation prompt, but never more than 10 applications: ` + delAppsCmdExamples[3] + `
+Delete with up to 16 delete operations in flight: ` + delAppsCmdExamples[4] + `
+Preview deleting every application in every App Hub location: ` + delAppsCmdExamples[5],
+}
+
+// confirmInput is where the confirmation prompt reads from
+var confirmInput io.Reader = os.Stdin
+
+// confirmDeletion asks the user to type the project ID before deleting more
+// than one application
+func confirmDeletion(in io.Reader, out io.Writer, projectID string, count int) error {
+	fmt.Fprintf(out, "%d applications will be deleted. Type the project ID (%s) to confirm: ", count, projectID)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("failed to read confirmation: %w", err)
+	}
+	if strings.TrimSpace(answer) != projectID {
+		return fmt.Errorf("deletion cancelled, the confirmation did not match the project ID. Use --yes to skip the confirmation")
+	}
+	return nil
+}
+
+// checkPlanDeletions enforces --max-deletions and, unless --yes is set, asks
+// for confirmation before merge or split deletes the emptied applications
+func checkPlanDeletions(projectID string, count, maxDeletions int, yes bool) error {
+	if maxDeletions > 0 && count > maxDeletions {
+		return fmt.Errorf("refusing to delete %d applications, more than --max-deletions=%d", count, maxDeletions)
+	}
+	if !yes {
+		return confirmDeletion(confirmInput, os.Stdout, projectID, count)
+	}
+	return nil
+}
+
+// newDeletionProgress returns a callback that prints a line as each
+// application finishes
+func newDeletionProgress(out io.Writer) func(result client.DeletionResult, done, total int) {
+	return func(result client.DeletionResult, done, total int) {
+		if result.Error != nil {
+			fmt.Fprintf(out, "[%d/%d] failed to delete %s in %s: %v\n", done, total, result.ID, result.Location, result.Error)
+			return
+		}
+		fmt.Fprintf(out, "[%d/%d] deleted %s in %s\n", done, total, result.ID, result.Location)
+	}
 }
 
 var delAppsCmdExamples = []string{
 	`apphub-app-creator apps delete --management-project $project --locations us-west1 --locations us-east1`,
 	`apphub-app-creator apps delete --name $name --management-project $project --locations us-west1`,
+	`apphub-app-creator apps delete --management-project $project --locations us-west1 --dry-run`,
+	`apphub-app-creator apps delete --management-project $project --locations us-west1 --yes --max-deletions 10`,
+	`apphub-app-creator apps delete --management-project $project --locations us-west1 --locations us-east1 --yes --concurrency 16`,
+	`apphub-app-creator apps delete --management-project $project --locations all --dry-run`,
 }
 
 func GetDelAppExample(i int) string {
@@ -69,8 +163,24 @@ func GetDelAppExample(i int) string {
 }
 
 func init() {
-	var name string
+	var name, snapshot string
+	var dryRun, yes, includeUnmanaged bool
+	var maxDeletions, concurrency int
 
 	DelAppsCmd.Flags().StringVarP(&name, "name", "",
 		"", "Name of the App Hub Application. If left empty, all applications in the region will be deleted")
+	DelAppsCmd.Flags().BoolVarP(&dryRun, "dry-run", "",
+		false, "List the applications, services and workloads that would be deleted without deleting them")
+	DelAppsCmd.Flags().BoolVarP(&yes, "yes", "",
+		false, "Skip the confirmation prompt when more than one application is deleted")
+	DelAppsCmd.Flags().IntVarP(&maxDeletions, "max-deletions", "",
+		0, "Refuse to delete if more than this number of applications would be deleted. 0 means no limit")
+	DelAppsCmd.Flags().BoolVarP(&includeUnmanaged, "include-unmanaged", "",
+		false, "Also delete applications that were not created by this tool")
+	DelAppsCmd.Flags().IntVarP(&concurrency, "concurrency", "",
+		client.DEFAULT_DELETION_CONCURRENCY, "Maximum number of delete operations in flight, shared across "+
+			"every application and location")
+	DelAppsCmd.Flags().StringVarP(&snapshot, "snapshot", "",
+		"", "Path of the snapshot written before deleting, used by apps restore. "+
+			"Defaults to apphub-snapshot-{project}-{time}.json in the current directory")
 }
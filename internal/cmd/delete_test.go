@@ -0,0 +1,98 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"internal/client"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestConfirmDeletion(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:    "Project ID typed",
+			input:   "my-project\n",
+			wantErr: false,
+		},
+		{
+			name:    "Project ID without newline",
+			input:   "my-project",
+			wantErr: false,
+		},
+		{
+			name:    "Yes is not enough",
+			input:   "y\n",
+			wantErr: true,
+		},
+		{
+			name:    "No input",
+			input:   "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := confirmDeletion(strings.NewReader(tt.input), &out, "my-project", 3)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("confirmDeletion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), "3 applications") {
+				t.Errorf("confirmDeletion() prompt = %s", out.String())
+			}
+		})
+	}
+}
+
+func TestCheckPlanDeletions(t *testing.T) {
+	defer func() { confirmInput = os.Stdin }()
+
+	if err := checkPlanDeletions("my-project", 2, 1, true); err == nil || !strings.Contains(err.Error(), "--max-deletions=1") {
+		t.Errorf("checkPlanDeletions() error = %v, want the max deletions error", err)
+	}
+	if err := checkPlanDeletions("my-project", 1, 0, true); err != nil {
+		t.Errorf("checkPlanDeletions() with --yes error = %v", err)
+	}
+	confirmInput = strings.NewReader("other-project\n")
+	if err := checkPlanDeletions("my-project", 1, 0, false); err == nil {
+		t.Errorf("checkPlanDeletions() deleted a single application without confirmation")
+	}
+	confirmInput = strings.NewReader("my-project\n")
+	if err := checkPlanDeletions("my-project", 1, 0, false); err != nil {
+		t.Errorf("checkPlanDeletions() error = %v after confirmation", err)
+	}
+}
+
+func TestDeletionProgress(t *testing.T) {
+	var out bytes.Buffer
+	progress := newDeletionProgress(&out)
+
+	progress(client.DeletionResult{ID: "shop", Location: "us-west1"}, 1, 2)
+	progress(client.DeletionResult{ID: "cart", Location: "us-east1", Error: fmt.Errorf("permission denied")}, 2, 2)
+
+	want := "[1/2] deleted shop in us-west1\n[2/2] failed to delete cart in us-east1: permission denied\n"
+	if out.String() != want {
+		t.Errorf("progress = %q, want %q", out.String(), want)
+	}
+}
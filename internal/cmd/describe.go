@@ -0,0 +1,88 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"internal/client"
+
+	"github.com/spf13/cobra"
+)
+
+// DescribeAppCmd to describe an application
+var DescribeAppCmd = &cobra.Command{
+	Use:   "describe",
+	Short: "Describe an App Hub Application",
+	Long:  "Describe an App Hub Application with every registered service and workload and their underlying resources",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		name := GetStringParam(cmd.Flag("name"))
+		output := GetStringParam(cmd.Flag("output"))
+
+		if managementProject == "" {
+			return fmt.Errorf("management project is a required field")
+		}
+		if len(locations) == 0 {
+			return fmt.Errorf("at least one location is required")
+		}
+		if name == "" {
+			return fmt.Errorf("name is a required field")
+		}
+		if output != "table" && output != "json" {
+			return fmt.Errorf("output must be one of table or json")
+		}
+		return
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		cmd.SilenceUsage = true
+
+		name := GetStringParam(cmd.Flag("name"))
+		output := GetStringParam(cmd.Flag("output"))
+		enrich, _ := cmd.Flags().GetBool("enrich")
+
+		description, err := client.DescribeApp(managementProject, name, locations, enrich)
+		if err != nil {
+			return err
+		}
+
+		if output == "json" {
+			return PrintJSON(description)
+		}
+		PrintApplicationDescription(description, enrich)
+		return nil
+	},
+	Example: `Describe an application: ` + describeAppCmdExamples[0] + `
+Describe an application with the CAIS labels and tags of its resources: ` + describeAppCmdExamples[1],
+}
+
+var describeAppCmdExamples = []string{
+	`apphub-app-creator apps describe --name $name --management-project $project --locations us-west1`,
+	`apphub-app-creator apps describe --name $name --management-project $project --locations us-west1 --enrich --output json`,
+}
+
+func GetDescribeAppExample(i int) string {
+	return describeAppCmdExamples[i]
+}
+
+func init() {
+	var name, output string
+	var enrich bool
+
+	DescribeAppCmd.Flags().StringVarP(&name, "name", "",
+		"", "Name of the App Hub Application")
+	DescribeAppCmd.Flags().StringVarP(&output, "output", "",
+		"table", "Output format, one of table or json")
+	DescribeAppCmd.Flags().BoolVarP(&enrich, "enrich", "",
+		false, "Read the labels and tags of the underlying resources from CAIS")
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"internal/client"
+
+	"github.com/spf13/cobra"
+)
+
+// DetachCmd to remove services and workloads from an application
+var DetachCmd = &cobra.Command{
+	Use:   "detach",
+	Short: "Detach services and workloads from an App Hub Application",
+	Long: "Remove specific services and workloads from an App Hub Application by ID, resource URI " +
+		"or the CAIS labels of the underlying resource, leaving the application in place",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		name := GetStringParam(cmd.Flag("name"))
+		ids, _ := cmd.Flags().GetStringArray("id")
+		uris, _ := cmd.Flags().GetStringArray("uri")
+		labels, _ := cmd.Flags().GetStringArray("label")
+
+		if managementProject == "" {
+			return fmt.Errorf("management project is a required field")
+		}
+		if len(locations) == 0 {
+			return fmt.Errorf("at least one location is required")
+		}
+		if name == "" {
+			return fmt.Errorf("name is a required field")
+		}
+		if len(ids) == 0 && len(uris) == 0 && len(labels) == 0 {
+			return fmt.Errorf("at least one of id, uri or label is required")
+		}
+		return
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		cmd.SilenceUsage = true
+
+		name := GetStringParam(cmd.Flag("name"))
+		ids, _ := cmd.Flags().GetStringArray("id")
+		uris, _ := cmd.Flags().GetStringArray("uri")
+		labels, _ := cmd.Flags().GetStringArray("label")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		includeUnmanaged, _ := cmd.Flags().GetBool("include-unmanaged")
+		snapshot := getSnapshotPath(GetStringParam(cmd.Flag("snapshot")), managementProject)
+
+		detached, err := client.DetachMembers(managementProject, name, locations, ids, uris, labels, snapshot,
+			includeUnmanaged, dryRun)
+		if err != nil {
+			return err
+		}
+
+		if len(detached) == 0 {
+			fmt.Println("No services or workloads matched the selectors")
+			return nil
+		}
+		if !dryRun {
+			fmt.Printf("Snapshot of the application written to %s\n", snapshot)
+		}
+		PrintDetachedMembers(detached, dryRun)
+		return nil
+	},
+	Example: `Detach a service by ID: ` + detachCmdExamples[0] + `
+Preview detaching every member whose resource has the label env=sandbox: ` + detachCmdExamples[1],
+}
+
+var detachCmdExamples = []string{
+	`apphub-app-creator apps detach --name $name --id $service --management-project $project --locations us-west1`,
+	`apphub-app-creator apps detach --name $name --label env=sandbox --management-project $project --locations us-west1 --dry-run`,
+}
+
+func GetDetachExample(i int) string {
+	return detachCmdExamples[i]
+}
+
+func init() {
+	var name, snapshot string
+	var ids, uris, labels []string
+	var dryRun, includeUnmanaged bool
+
+	DetachCmd.Flags().StringVarP(&name, "name", "",
+		"", "Name of the App Hub Application")
+	DetachCmd.Flags().StringArrayVarP(&ids, "id", "",
+		[]string{}, "ID of a service or workload to detach")
+	DetachCmd.Flags().StringArrayVarP(&uris, "uri", "",
+		[]string{}, "Resource URI of a service or workload to detach")
+	DetachCmd.Flags().StringArrayVarP(&labels, "label", "",
+		[]string{}, "Detach members whose underlying resource has this CAIS label, of the format key=value. "+
+			"The value supports globs and /regex/")
+	DetachCmd.Flags().StringVarP(&snapshot, "snapshot", "",
+		"", "Path of the snapshot written before detaching, used by apps restore. "+
+			"Defaults to apphub-snapshot-{project}-{time}.json in the current directory")
+	DetachCmd.Flags().BoolVarP(&dryRun, "dry-run", "",
+		false, "List the services and workloads that would be detached without detaching them")
+	DetachCmd.Flags().BoolVarP(&includeUnmanaged, "include-unmanaged", "",
+		false, "Allow detaching from an application that was not created by this tool")
+}
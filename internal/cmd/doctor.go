@@ -0,0 +1,93 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"internal/client"
+
+	"github.com/spf13/cobra"
+)
+
+// DoctorCmd to check the prerequisites of the tool
+var DoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check permissions, APIs and the App Hub host project setup",
+	Long: "Check the prerequisites of apphub-app-creator: Application Default Credentials, the App Hub and " +
+		"Cloud Asset APIs, IAM permissions on the management project and parent, the host project or folder " +
+		"setup and App Hub reads. Prints a checklist with remediation commands and exits with an error when a check fails",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		output := GetStringParam(cmd.Flag("output"))
+
+		if managementProject == "" && parent == "" {
+			return fmt.Errorf("management project is a required field")
+		}
+		if output != "table" && output != "json" {
+			return fmt.Errorf("output must be one of table or json")
+		}
+		return
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		cmd.SilenceUsage = true
+
+		output := GetStringParam(cmd.Flag("output"))
+
+		if managementProject == "" {
+			managementProject, err = GetProjectID(parent)
+			if err != nil {
+				return err
+			}
+		}
+
+		checks := client.RunDoctor(parent, managementProject, locations)
+
+		if output == "json" {
+			if err = PrintJSON(checks); err != nil {
+				return err
+			}
+		} else {
+			PrintDoctorChecks(checks)
+		}
+
+		var failed int
+		for _, check := range checks {
+			if check.Status == "FAIL" {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+		}
+		return nil
+	},
+	Example: `Check the setup of a management project: ` + doctorCmdExamples[0] + `
+Check a folder setup in CI: ` + doctorCmdExamples[1],
+}
+
+var doctorCmdExamples = []string{
+	`apphub-app-creator apps doctor --management-project $project --locations us-west1`,
+	`apphub-app-creator apps doctor --parent folders/$folder --management-project $project --output json --log-level off`,
+}
+
+func GetDoctorExample(i int) string {
+	return doctorCmdExamples[i]
+}
+
+func init() {
+	var output string
+
+	DoctorCmd.Flags().StringVarP(&output, "output", "",
+		"table", "Output format, one of table or json")
+}
@@ -0,0 +1,189 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"internal/client"
+	"internal/clilog"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// maxNotificationSize bounds the request body, a Pub/Sub message of at most
+// 10 MB is base64 encoded in the push request
+const maxNotificationSize = 16 << 20
+
+// FeedCmd to register assets from CAIS feed notifications
+var FeedCmd = &cobra.Command{
+	Use:   "feed",
+	Short: "Register assets in App Hub from Cloud Asset Inventory feed notifications",
+	Long: "Serve an HTTP endpoint that accepts Cloud Asset Inventory feed notifications, delivered by a Pub/Sub push " +
+		"subscription, and registers, moves or detaches the changed asset using the applications chosen by a rules file. " +
+		"The endpoint does not authenticate requests, deploy it behind IAM and a push subscription with OIDC authentication",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if GetStringParam(cmd.Flag("rules")) == "" {
+			return fmt.Errorf("rules is a required field")
+		}
+		if managementProject == "" && parent == "" {
+			return fmt.Errorf("management project is a required field")
+		}
+		if len(locations) == 0 {
+			return fmt.Errorf("at least one location must be set")
+		}
+		return
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		cmd.SilenceUsage = true
+
+		rules := GetStringParam(cmd.Flag("rules"))
+		attributes := GetStringParam(cmd.Flag("attributes"))
+		port := GetStringParam(cmd.Flag("port"))
+		includeUnmanaged, _ := cmd.Flags().GetBool("include-unmanaged")
+
+		logger := clilog.GetLogger()
+
+		if managementProject == "" {
+			managementProject, err = GetProjectID(parent)
+			if err != nil {
+				return err
+			}
+		}
+
+		var rulesData, attributesData, configData []byte
+		if rulesData, err = os.ReadFile(rules); err != nil {
+			return err
+		}
+		if attributes != "" {
+			if attributesData, err = os.ReadFile(attributes); err != nil {
+				return err
+			}
+		}
+		if configFile != "" {
+			if configData, err = os.ReadFile(configFile); err != nil {
+				return err
+			}
+		}
+		if err = client.SetAssetTypeRegistry(configData); err != nil {
+			return err
+		}
+		if err = client.SetExclusions(configData, nil, nil, nil, nil, nil, false); err != nil {
+			return err
+		}
+
+		client.SetProvenance(cmd.Root().Version, "feed")
+		client.SetIncludeUnmanaged(includeUnmanaged)
+
+		feed, err := client.NewFeed(managementProject, locations, rulesData, attributesData)
+		if err != nil {
+			return err
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxNotificationSize))
+			if err != nil {
+				code := http.StatusBadRequest
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					code = http.StatusRequestEntityTooLarge
+				}
+				http.Error(w, err.Error(), code)
+				return
+			}
+			temporalAsset, err := client.ParseFeedNotification(payload)
+			if err != nil {
+				logger.Warn("Rejecting feed notification", "error", err)
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			// a transient failure is returned as a server error so Pub/Sub delivers the notification again
+			result, err := feed.Process(temporalAsset)
+			if err != nil {
+				logger.Error("Failed to process feed notification", "assetName", result.Asset, "error", err)
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			logger.Info("Processed feed notification", "assetName", result.Asset, "action", result.Action,
+				"application", result.Application, "reason", result.Reason)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(result)
+		})
+		mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, "ok")
+		})
+		server := &http.Server{Addr: ":" + port, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+
+		logger.Info("Serving feed notifications", "port", port, "managementProject", managementProject)
+		if err = server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("feed server failed: %w", err)
+		}
+		printRunID()
+		return nil
+	},
+	Example: `Serve feed notifications: ` + feedCmdExamples[0] + `
+Serve feed notifications for global applications: ` + feedCmdExamples[1],
+}
+
+var feedCmdExamples = []string{
+	`apphub-app-creator apps feed --management-project $mp --locations us-west1 --rules rules.yaml`,
+	`apphub-app-creator apps feed --parent projects/$project --locations us-west1 --locations us-east1 --rules rules.yaml --config config.yaml`,
+}
+
+func GetFeedExample(i int) string {
+	return feedCmdExamples[i]
+}
+
+func init() {
+	var rules, attributes string
+	var includeUnmanaged bool
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	FeedCmd.Flags().StringVarP(&rules, "rules", "",
+		"", "Path to a YAML rules file that chooses the application of each asset")
+	FeedCmd.Flags().StringVarP(&attributes, "attributes", "",
+		"", "Path to a json file containing App Hub attributes")
+	FeedCmd.Flags().StringVarP(&port, "port", "",
+		port, "Port receiving the notifications, defaults to the PORT environment variable or 8080")
+	FeedCmd.Flags().BoolVarP(&includeUnmanaged, "include-unmanaged", "",
+		false, "Allow registering services and workloads in existing applications that were not created by this tool")
+}
@@ -19,6 +19,7 @@ import (
 	"internal/client"
 	"os"
 	"regexp"
+	"slices"
 
 	"github.com/spf13/cobra"
 )
@@ -52,6 +53,9 @@ var GenAppsCmd = &cobra.Command{
 		if len(locations) == 0 {
 			return fmt.Errorf("at least one location is required")
 		}
+		if slices.Contains(locations, client.ALL_LOCATIONS) {
+			return fmt.Errorf("generate requires explicit locations, %s is not supported", client.ALL_LOCATIONS)
+		}
 		if labelValue != "" && labelKey == "" {
 			return fmt.Errorf("label-value must be used with label-key")
 		}
@@ -72,6 +76,19 @@ var GenAppsCmd = &cobra.Command{
 		if len(projectKeys) > 1 && !IsFolder(parent) {
 			return fmt.Errorf("multiple project-keys is only allowed when parent=folders/{folder}")
 		}
+
+		namespaceGroupBy := GetStringParam(cmd.Flag("namespace-group-by"))
+		if perK8sNamespace, _ := cmd.Flags().GetBool("per-k8s-namespace"); namespaceGroupBy != "" && !perK8sNamespace {
+			return fmt.Errorf("namespace-group-by must be used with per-k8s-namespace")
+		}
+		if err = client.ValidateNamespaceGroup(namespaceGroupBy); err != nil {
+			return err
+		}
+
+		k8sAppLabels, _ := cmd.Flags().GetStringArray("k8s-app-labels")
+		if perK8sAppLabel, _ := cmd.Flags().GetBool("per-k8s-app-label"); len(k8sAppLabels) > 0 && !perK8sAppLabel {
+			return fmt.Errorf("k8s-app-labels must be used with per-k8s-app-label")
+		}
 		return
 	},
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
@@ -84,16 +101,29 @@ var GenAppsCmd = &cobra.Command{
 		tagKey := GetStringParam(cmd.Flag("tag-key"))
 		tagValue := GetStringParam(cmd.Flag("tag-value"))
 		attributes := GetStringParam(cmd.Flag("attributes"))
+		namespaceGroupBy := GetStringParam(cmd.Flag("namespace-group-by"))
 		assetTypes := GetStringParam(cmd.Flag("asset-types"))
+		assetTypeList, _ := cmd.Flags().GetStringArray("asset-type")
 		contains := GetStringParam(cmd.Flag("contains"))
 		appName := GetStringParam(cmd.Flag("app-name"))
+		rules := GetStringParam(cmd.Flag("rules"))
+		excludeProjects, _ := cmd.Flags().GetStringArray("exclude-projects")
+		excludeAssetTypes, _ := cmd.Flags().GetStringArray("exclude-asset-types")
+		excludeLabels, _ := cmd.Flags().GetStringArray("exclude-labels")
+		excludeNamespaces, _ := cmd.Flags().GetStringArray("exclude-namespaces")
+		excludeNames, _ := cmd.Flags().GetStringArray("exclude-names")
+		k8sAppLabels, _ := cmd.Flags().GetStringArray("k8s-app-labels")
 		perK8sNamespace, _ := cmd.Flags().GetBool("per-k8s-namespace")
 		perK8sAppLabel, _ := cmd.Flags().GetBool("per-k8s-app-label")
 		reportOnly, _ := cmd.Flags().GetBool("report-only")
 		autoDetect, _ := cmd.Flags().GetBool("auto-detect")
+		includeUnmanaged, _ := cmd.Flags().GetBool("include-unmanaged")
+		reassign, _ := cmd.Flags().GetBool("reassign")
+		attachProjects, _ := cmd.Flags().GetBool("attach-projects")
 
-		var attributesData, assetTypesData []byte
+		var attributesData, assetTypesData, rulesData, configData []byte
 		var generatedApplications map[string][]string
+		var unmatchedAssets []string
 
 		if managementProject == "" {
 			managementProject, err = GetProjectID(parent)
@@ -113,7 +143,68 @@ var GenAppsCmd = &cobra.Command{
 			}
 		}
 
-		if autoDetect {
+		if configFile != "" {
+			if _, err := os.Stat(configFile); os.IsNotExist(err) {
+				return err
+			}
+
+			configData, err = os.ReadFile(configFile)
+			if err != nil {
+				return err
+			}
+		}
+
+		if assetTypes != "" {
+			if _, err := os.Stat(assetTypes); os.IsNotExist(err) {
+				return err
+			}
+
+			assetTypesData, err = os.ReadFile(assetTypes)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err = client.SetAssetTypeRegistry(configData); err != nil {
+			return err
+		}
+
+		if err = client.SetAssetTypes(assetTypesData, assetTypeList); err != nil {
+			return err
+		}
+
+		if err = client.SetExclusions(configData,
+			excludeProjects,
+			excludeAssetTypes,
+			excludeLabels,
+			excludeNamespaces,
+			excludeNames,
+			reportOnly); err != nil {
+			return err
+		}
+
+		client.SetProvenance(cmd.Root().Version, getGenerateMode(cmd))
+		client.SetIncludeUnmanaged(includeUnmanaged)
+		client.SetReassign(reassign)
+		client.SetAttachProjects(attachProjects)
+
+		if rules != "" {
+			if _, err := os.Stat(rules); os.IsNotExist(err) {
+				return err
+			}
+
+			rulesData, err = os.ReadFile(rules)
+			if err != nil {
+				return err
+			}
+
+			generatedApplications, unmatchedAssets, err = client.GenerateFromRules(parent,
+				managementProject,
+				locations,
+				rulesData,
+				attributesData,
+				reportOnly)
+		} else if autoDetect {
 			generatedApplications, err = client.GenerateFromAll(parent,
 				managementProject,
 				locations,
@@ -124,11 +215,13 @@ var GenAppsCmd = &cobra.Command{
 				managementProject,
 				locations,
 				attributesData,
+				namespaceGroupBy,
 				reportOnly)
 		} else if perK8sAppLabel {
 			generatedApplications, err = client.GenerateKubernetesApps(parent,
 				managementProject,
 				locations,
+				k8sAppLabels,
 				attributesData,
 				reportOnly)
 		} else if logLabelKey != "" {
@@ -147,20 +240,8 @@ var GenAppsCmd = &cobra.Command{
 				projectKeys,
 				locations,
 				attributesData,
-				nil,
 				reportOnly)
 		} else {
-			if assetTypes != "" {
-				if _, err := os.Stat(assetTypes); os.IsNotExist(err) {
-					return err
-				}
-
-				assetTypesData, err = os.ReadFile(assetTypes)
-				if err != nil {
-					return err
-				}
-			}
-
 			if labelValue == "" {
 				labelValue = "*"
 			}
@@ -174,15 +255,22 @@ var GenAppsCmd = &cobra.Command{
 				contains,
 				locations,
 				attributesData,
-				assetTypesData,
 				reportOnly)
 		}
+		printRunID()
 		if err != nil {
 			return err
 		}
 		if reportOnly {
 			PrintGeneratedApplication(generatedApplications)
 		}
+		if len(unmatchedAssets) > 0 {
+			PrintUnmatchedAssets(unmatchedAssets)
+		}
+		if reportOnly {
+			PrintExcludedAssets(client.GetExcludedAssets())
+		}
+		PrintUnmanagedApplications(client.GetUnmanagedApplications())
 		return nil
 	},
 	Example: `Create apps by searching CAIS based on GCP Resource labels in the following locations: ` + genAppsCmdExamples[0] + `
@@ -199,7 +287,13 @@ Generate a report of discovered assets: ` + genAppsCmdExamples[5] + `
 
 Automatically detect applications based on well known labels and tags: ` + genAppsCmdExamples[6] + `
 
-Generate an application per project or list of projects: ` + genAppsCmdExamples[7],
+Generate an application per project or list of projects: ` + genAppsCmdExamples[7] + `
+
+Group assets into applications using an ordered rules file: ` + genAppsCmdExamples[8] + `
+
+Create one application per Kubernetes namespace across the clusters of a fleet: ` + genAppsCmdExamples[9] + `
+
+Create one application per Helm release, falling back to the app label: ` + genAppsCmdExamples[10],
 }
 
 var genAppsCmdExamples = []string{
@@ -211,6 +305,9 @@ var genAppsCmdExamples = []string{
 	`apphub-app-creator apps generate --parent projects/$project --management-project $mp --locations us-west1 --label-key $label_key --report-only=true`,
 	`apphub-app-creator apps generate --parent projects/$project --management-project $mp --locations us-west1 --auto-detect=true --report-only=true`,
 	`apphub-app-creator apps generate --parent folders/$folder --management-project $mp --locations us-west1 --project-keys proj1 --project-keys proj2 --app-name my-app`,
+	`apphub-app-creator apps generate --parent folders/$folder --management-project $mp --locations us-west1 --rules ./samples/rules.yaml --report-only=true`,
+	`apphub-app-creator apps generate --parent projects/$project --management-project $mp --locations us-west1 --locations us-east1 --per-k8s-namespace=true --namespace-group-by namespace-fleet`,
+	`apphub-app-creator apps generate --parent projects/$project --management-project $mp --locations us-west1 --per-k8s-app-label=true --k8s-app-labels app.kubernetes.io/instance --k8s-app-labels app`,
 }
 
 func GetGenAppExample(i int) string {
@@ -223,10 +320,27 @@ func isValidAppName(s string) bool {
 	return isValid
 }
 
+// generateModeFlags select how assets are grouped into applications, exactly one is required
+var generateModeFlags = []string{
+	"auto-detect", "label-key", "tag-key", "contains", "log-label-key",
+	"per-k8s-namespace", "per-k8s-app-label", "project-keys", "rules",
+}
+
+// getGenerateMode returns the flag that selected the generate mode
+func getGenerateMode(cmd *cobra.Command) string {
+	for _, name := range generateModeFlags {
+		if cmd.Flags().Changed(name) {
+			return name
+		}
+	}
+	return ""
+}
+
 func init() {
 	var labelKey, labelValue, tagKey, tagValue, contains, logLabelKey, logLabelValue string
-	var attributes, assetTypes, appName string
-	var perK8sNamespace, perK8sAppLabel, reportOnly, autoDetect bool
+	var attributes, assetTypes, appName, rules, namespaceGroupBy string
+	var assetTypeList, excludeProjects, excludeAssetTypes, excludeLabels, excludeNamespaces, excludeNames, k8sAppLabels []string
+	var perK8sNamespace, perK8sAppLabel, reportOnly, autoDetect, includeUnmanaged, reassign, attachProjects bool
 
 	GenAppsCmd.Flags().StringVarP(&labelKey, "label-key", "",
 		"", "Key of the GCP resource label to use for grouping assets into applications.")
@@ -250,17 +364,43 @@ func init() {
 		"", "Path to a json file containing App Hub attributes")
 	GenAppsCmd.Flags().BoolVarP(&perK8sNamespace, "per-k8s-namespace", "",
 		false, "Create one App Hub application per discovered Kubernetes namespace.")
+	GenAppsCmd.Flags().StringVarP(&namespaceGroupBy, "namespace-group-by", "",
+		"", "Group namespaces by cluster (default), namespace, namespace-project or namespace-fleet. "+
+			"Should be used in conjunction with per-k8s-namespace")
 	GenAppsCmd.Flags().BoolVarP(&perK8sAppLabel, "per-k8s-app-label", "",
 		false, "Create one App Hub application per app.kubernetes.io/name label value.")
+	GenAppsCmd.Flags().StringArrayVarP(&k8sAppLabels, "k8s-app-labels", "",
+		[]string{}, "An ordered list of Kubernetes label keys to group by, the first key with a value is used. "+
+			"Use app.kubernetes.io/instance to group by Helm release. Should be used in conjunction with per-k8s-app-label")
 	GenAppsCmd.Flags().StringVarP(&assetTypes, "asset-types", "",
-		"", "Path to a CSV file containing CAIS Asset Types")
+		"", "Path to a file containing comma or newline separated CAIS Asset Types")
+	GenAppsCmd.Flags().StringArrayVarP(&assetTypeList, "asset-type", "",
+		[]string{}, "CAIS Asset Type to search. Can be repeated or comma separated")
 	GenAppsCmd.Flags().BoolVarP(&reportOnly, "report-only", "",
 		false, "Generates a report of discovered assets without creating applications or registering services/workloads.")
 	GenAppsCmd.Flags().BoolVarP(&autoDetect, "auto-detect", "",
 		false, "Automatically detect applications using well known identifiers through labels and tags.")
-
-	GenAppsCmd.MarkFlagsMutuallyExclusive("auto-detect", "label-key", "tag-key", "contains", "log-label-key", "per-k8s-namespace", "per-k8s-app-label", "project-keys")
+	GenAppsCmd.Flags().StringVarP(&rules, "rules", "",
+		"", "Path to a YAML file containing ordered rules for grouping assets into applications.")
+	GenAppsCmd.Flags().StringArrayVarP(&excludeProjects, "exclude-projects", "",
+		[]string{}, "Project ids or numbers to exclude. Supports globs and /regex/")
+	GenAppsCmd.Flags().StringArrayVarP(&excludeAssetTypes, "exclude-asset-types", "",
+		[]string{}, "CAIS Asset Types to exclude. Supports globs and /regex/")
+	GenAppsCmd.Flags().StringArrayVarP(&excludeLabels, "exclude-labels", "",
+		[]string{}, "Labels to exclude, of the format key=value. The value supports globs and /regex/")
+	GenAppsCmd.Flags().StringArrayVarP(&excludeNamespaces, "exclude-namespaces", "",
+		[]string{}, "Kubernetes namespaces to exclude, in addition to the GKE system namespaces")
+	GenAppsCmd.Flags().StringArrayVarP(&excludeNames, "exclude-names", "",
+		[]string{}, "Resource name patterns to exclude. Supports globs and /regex/")
+	GenAppsCmd.Flags().BoolVarP(&includeUnmanaged, "include-unmanaged", "",
+		false, "Allow registering services and workloads in existing applications that were not created by this tool")
+	GenAppsCmd.Flags().BoolVarP(&reassign, "reassign", "",
+		false, "Move services and workloads that are registered in another application to the generated application")
+	GenAppsCmd.Flags().BoolVarP(&attachProjects, "attach-projects", "",
+		false, "Attach the projects of the assets to the management project as service projects when they are not attached")
+
+	GenAppsCmd.MarkFlagsMutuallyExclusive(generateModeFlags...)
 	GenAppsCmd.MarkFlagsMutuallyExclusive("label-value", "tag-value")
 	GenAppsCmd.MarkFlagsRequiredTogether("project-keys", "app-name")
-	GenAppsCmd.MarkFlagsOneRequired("auto-detect", "label-key", "tag-key", "contains", "log-label-key", "per-k8s-namespace", "per-k8s-app-label", "project-keys")
+	GenAppsCmd.MarkFlagsOneRequired(generateModeFlags...)
 }
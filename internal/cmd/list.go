@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"internal/client"
+
+	"github.com/spf13/cobra"
+)
+
+// ListAppsCmd to list applications
+var ListAppsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List App Hub Applications",
+	Long:  "List App Hub Applications from multiple regions with their attributes and member counts",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		output := GetStringParam(cmd.Flag("output"))
+
+		if managementProject == "" {
+			return fmt.Errorf("management project is a required field")
+		}
+		if len(locations) == 0 {
+			return fmt.Errorf("at least one location is required")
+		}
+		if output != "table" && output != "json" && output != "csv" {
+			return fmt.Errorf("output must be one of table, json or csv")
+		}
+		return
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		cmd.SilenceUsage = true
+
+		output := GetStringParam(cmd.Flag("output"))
+		names, _ := cmd.Flags().GetStringArray("name")
+		filters, _ := cmd.Flags().GetStringArray("filter")
+
+		appLocations, err := client.ResolveLocations(managementProject, locations)
+		if err != nil {
+			return err
+		}
+
+		applications, err := client.ListApps(managementProject, appLocations, names, filters)
+		if err != nil {
+			return err
+		}
+
+		switch output {
+		case "json":
+			return PrintJSON(applications)
+		case "csv":
+			return PrintApplicationsCSV(applications)
+		default:
+			PrintApplications(applications)
+		}
+		return nil
+	},
+	Example: `List all applications in the following locations: ` + listAppsCmdExamples[0] + `
+List mission critical applications without an operator owner: ` + listAppsCmdExamples[1] + `
+List applications whose name starts with pay as CSV: ` + listAppsCmdExamples[2],
+}
+
+var listAppsCmdExamples = []string{
+	`apphub-app-creator apps list --management-project $project --locations us-west1 --locations global`,
+	`apphub-app-creator apps list --management-project $project --locations us-west1 --filter criticality=MISSION_CRITICAL --filter operator-owner=`,
+	`apphub-app-creator apps list --management-project $project --locations us-west1 --name "pay*" --output csv`,
+}
+
+func GetListAppExample(i int) string {
+	return listAppsCmdExamples[i]
+}
+
+func init() {
+	var output string
+	var names, filters []string
+
+	ListAppsCmd.Flags().StringVarP(&output, "output", "",
+		"table", "Output format, one of table, json or csv")
+	ListAppsCmd.Flags().StringArrayVarP(&names, "name", "",
+		[]string{}, "Only list applications whose name matches this glob or /regex/")
+	ListAppsCmd.Flags().StringArrayVarP(&filters, "filter", "",
+		[]string{}, "Only list applications whose attribute matches, of the format key=value. Keys are display-name, "+
+			"scope, criticality, environment, developer-owner, operator-owner and business-owner. "+
+			"An empty value matches applications without the attribute")
+}
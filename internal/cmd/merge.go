@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"internal/client"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// MergeAppsCmd to merge applications
+var MergeAppsCmd = &cobra.Command{
+	Use:   "merge",
+	Short: "Merge App Hub Applications",
+	Long: "Move every service and workload of the source applications into the target application, " +
+		"creating it if needed, and delete the emptied source applications",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		from, _ := cmd.Flags().GetStringArray("from")
+		into := GetStringParam(cmd.Flag("into"))
+
+		if managementProject == "" {
+			return fmt.Errorf("management project is a required field")
+		}
+		if len(locations) == 0 {
+			return fmt.Errorf("at least one location is required")
+		}
+		if len(from) == 0 {
+			return fmt.Errorf("at least one source application is required")
+		}
+		if into == "" {
+			return fmt.Errorf("into is a required field")
+		}
+		return
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		cmd.SilenceUsage = true
+
+		from, _ := cmd.Flags().GetStringArray("from")
+		into := GetStringParam(cmd.Flag("into"))
+		attributes := GetStringParam(cmd.Flag("attributes"))
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		includeUnmanaged, _ := cmd.Flags().GetBool("include-unmanaged")
+		yes, _ := cmd.Flags().GetBool("yes")
+		maxDeletions, _ := cmd.Flags().GetInt("max-deletions")
+		snapshot := GetStringParam(cmd.Flag("snapshot"))
+
+		var attributesData []byte
+		if attributes != "" {
+			if attributesData, err = os.ReadFile(attributes); err != nil {
+				return err
+			}
+		}
+
+		client.SetProvenance(cmd.Root().Version, "merge")
+		client.SetIncludeUnmanaged(includeUnmanaged)
+
+		plan, err := client.PlanMerge(managementProject, from, into, locations, attributesData, includeUnmanaged)
+		if err != nil {
+			return err
+		}
+
+		PrintReorganizationPlan(plan)
+
+		if dryRun {
+			return nil
+		}
+		if len(plan.Delete) > 0 {
+			if err = checkPlanDeletions(managementProject, len(plan.Delete), maxDeletions, yes); err != nil {
+				return err
+			}
+			if err = writeSnapshot(snapshot, plan.Delete); err != nil {
+				return err
+			}
+		}
+		err = client.ApplyPlan(managementProject, plan)
+		printRunID()
+		return err
+	},
+	Example: `Merge two applications into a new application: ` + mergeAppsCmdExamples[0] + `
+Preview merging an application into an existing application: ` + mergeAppsCmdExamples[1],
+}
+
+var mergeAppsCmdExamples = []string{
+	`apphub-app-creator apps merge --from checkout --from checkout-api --into checkout-all --management-project $project --locations us-west1`,
+	`apphub-app-creator apps merge --from checkout-api --into checkout --management-project $project --locations us-west1 --dry-run`,
+}
+
+func GetMergeAppExample(i int) string {
+	return mergeAppsCmdExamples[i]
+}
+
+func init() {
+	var into, attributes, snapshot string
+	var from []string
+	var dryRun, includeUnmanaged, yes bool
+	var maxDeletions int
+
+	MergeAppsCmd.Flags().StringArrayVarP(&from, "from", "",
+		[]string{}, "Name of an App Hub Application to merge")
+	MergeAppsCmd.Flags().StringVarP(&into, "into", "",
+		"", "Name of the App Hub Application to merge into")
+	MergeAppsCmd.Flags().StringVarP(&attributes, "attributes", "",
+		"", "Path to a json file containing App Hub attributes for the target application. "+
+			"Defaults to the combined attributes of the source applications")
+	MergeAppsCmd.Flags().StringVarP(&snapshot, "snapshot", "",
+		"", "Path of the snapshot written before deleting emptied applications, used by apps restore. "+
+			"Defaults to apphub-snapshot-{project}-{time}.json in the current directory")
+	MergeAppsCmd.Flags().BoolVarP(&dryRun, "dry-run", "",
+		false, "Print the plan without changing any application")
+	MergeAppsCmd.Flags().BoolVarP(&yes, "yes", "",
+		false, "Skip the confirmation prompt before deleting the emptied applications")
+	MergeAppsCmd.Flags().IntVarP(&maxDeletions, "max-deletions", "",
+		0, "Refuse to proceed if more than this number of applications would be deleted. 0 means no limit")
+	MergeAppsCmd.Flags().BoolVarP(&includeUnmanaged, "include-unmanaged", "",
+		false, "Allow merging applications that were not created by this tool")
+}
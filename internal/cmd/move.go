@@ -0,0 +1,102 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"internal/client"
+
+	"github.com/spf13/cobra"
+)
+
+// MoveCmd to move services and workloads between applications
+var MoveCmd = &cobra.Command{
+	Use:   "move",
+	Short: "Move services and workloads to another App Hub Application",
+	Long: "Find the App Hub Application that holds a discovered service or workload, remove it from that " +
+		"application and register it in the target application with the same attributes. If the " +
+		"registration in the target application fails, the original registration is restored",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		name := GetStringParam(cmd.Flag("name"))
+		discoveredNames, _ := cmd.Flags().GetStringArray("discovered-name")
+		uris, _ := cmd.Flags().GetStringArray("uri")
+
+		if managementProject == "" {
+			return fmt.Errorf("management project is a required field")
+		}
+		if len(locations) == 0 {
+			return fmt.Errorf("at least one location is required")
+		}
+		if name == "" {
+			return fmt.Errorf("name is a required field")
+		}
+		if len(discoveredNames) == 0 && len(uris) == 0 {
+			return fmt.Errorf("at least one of discovered-name or uri is required")
+		}
+		return
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		cmd.SilenceUsage = true
+
+		name := GetStringParam(cmd.Flag("name"))
+		discoveredNames, _ := cmd.Flags().GetStringArray("discovered-name")
+		uris, _ := cmd.Flags().GetStringArray("uri")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		includeUnmanaged, _ := cmd.Flags().GetBool("include-unmanaged")
+
+		moved, err := client.MoveMembers(managementProject, name, locations, discoveredNames, uris,
+			includeUnmanaged, dryRun)
+		printRunID()
+		if len(moved) > 0 {
+			PrintMovedMembers(moved, dryRun)
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(moved) == 0 {
+			fmt.Println("No services or workloads to move")
+		}
+		return nil
+	},
+	Example: `Move a service to another application: ` + moveCmdExamples[0] + `
+Preview moving a resource by URI: ` + moveCmdExamples[1],
+}
+
+var moveCmdExamples = []string{
+	`apphub-app-creator apps move --name $name --discovered-name $discoveredName --management-project $project --locations us-west1`,
+	`apphub-app-creator apps move --name $name --uri $uri --management-project $project --locations us-west1 --locations global --dry-run`,
+}
+
+func GetMoveExample(i int) string {
+	return moveCmdExamples[i]
+}
+
+func init() {
+	var name string
+	var discoveredNames, uris []string
+	var dryRun, includeUnmanaged bool
+
+	MoveCmd.Flags().StringVarP(&name, "name", "",
+		"", "Name of the App Hub Application to move the services and workloads to")
+	MoveCmd.Flags().StringArrayVarP(&discoveredNames, "discovered-name", "",
+		[]string{}, "Name of a discovered service or workload to move")
+	MoveCmd.Flags().StringArrayVarP(&uris, "uri", "",
+		[]string{}, "Resource URI of a service or workload to move")
+	MoveCmd.Flags().BoolVarP(&dryRun, "dry-run", "",
+		false, "List the services and workloads that would be moved without moving them")
+	MoveCmd.Flags().BoolVarP(&includeUnmanaged, "include-unmanaged", "",
+		false, "Allow moving from or to an application that was not created by this tool")
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"internal/client"
+
+	"github.com/spf13/cobra"
+)
+
+// RestoreAppsCmd to restore applications from a snapshot
+var RestoreAppsCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore App Hub Applications from a snapshot",
+	Long: "Recreate the applications saved in a snapshot written before a delete, and register their " +
+		"services and workloads again. Members whose discovered resources no longer exist are reported",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if GetStringParam(cmd.Flag("snapshot")) == "" {
+			return fmt.Errorf("snapshot is a required field")
+		}
+		return
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		cmd.SilenceUsage = true
+
+		snapshot := GetStringParam(cmd.Flag("snapshot"))
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		report, err := client.RestoreSnapshot(snapshot, dryRun)
+		if err != nil {
+			return err
+		}
+
+		PrintRestoreReport(report)
+		printRunID()
+		return nil
+	},
+	Example: `Restore the applications of a snapshot: ` + restoreAppsCmdExamples[0] + `
+Preview the restore: ` + restoreAppsCmdExamples[1],
+}
+
+var restoreAppsCmdExamples = []string{
+	`apphub-app-creator apps restore --snapshot apphub-snapshot-$project-20250101-120000.json`,
+	`apphub-app-creator apps restore --snapshot apphub-snapshot-$project-20250101-120000.json --dry-run`,
+}
+
+func GetRestoreAppExample(i int) string {
+	return restoreAppsCmdExamples[i]
+}
+
+func init() {
+	var snapshot string
+	var dryRun bool
+
+	RestoreAppsCmd.Flags().StringVarP(&snapshot, "snapshot", "",
+		"", "Path of a snapshot written by apps delete, merge or split")
+	RestoreAppsCmd.Flags().BoolVarP(&dryRun, "dry-run", "",
+		false, "Look up the discovered resources and report what would be restored without creating anything")
+}
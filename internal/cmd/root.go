@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"internal/client"
 	"internal/clilog"
 	"io"
 	"log/slog"
@@ -58,6 +59,12 @@ var RootCmd = &cobra.Command{
 			})
 		}
 
+		runID, err := newRunID()
+		if err != nil {
+			return err
+		}
+		client.SetJournal(journal, runID)
+
 		logger := clilog.GetLogger()
 		if !disableCheck {
 			latestVersion, _ := getLatestVersion()
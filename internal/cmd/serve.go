@@ -0,0 +1,506 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"internal/client"
+	"internal/clilog"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// ServeCmd to expose generate, plan, list, describe and delete as a REST API
+var ServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a REST API to generate, plan, list, describe and delete applications",
+	Long: "Serve a REST API backed by the same functions as the commands: report-only generation, plan, apply, " +
+		"list, describe and delete. Results are returned as JSON. With --read-only, apply and delete are rejected, and " +
+		"with --pin-scope, requests are limited to the parent, management project and locations of the flags. " +
+		"The API does not authenticate requests, deploy it behind IAM or an authenticating proxy",
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		cmd.SilenceUsage = true
+
+		port := GetStringParam(cmd.Flag("port"))
+		readOnly, _ := cmd.Flags().GetBool("read-only")
+		includeUnmanaged, _ := cmd.Flags().GetBool("include-unmanaged")
+		reassign, _ := cmd.Flags().GetBool("reassign")
+		attachProjects, _ := cmd.Flags().GetBool("attach-projects")
+		pinScope, _ := cmd.Flags().GetBool("pin-scope")
+
+		if pinScope {
+			if parent == "" || len(locations) == 0 {
+				return fmt.Errorf("pin-scope requires the parent and locations")
+			}
+			if managementProject == "" {
+				if managementProject, err = GetProjectID(parent); err != nil {
+					return err
+				}
+			}
+		}
+
+		logger := clilog.GetLogger()
+
+		var configData []byte
+		if configFile != "" {
+			if configData, err = os.ReadFile(configFile); err != nil {
+				return err
+			}
+		}
+		if err = client.SetAssetTypeRegistry(configData); err != nil {
+			return err
+		}
+		if err = client.SetExclusions(configData, nil, nil, nil, nil, nil, false); err != nil {
+			return err
+		}
+
+		client.SetIncludeUnmanaged(includeUnmanaged)
+		client.SetReassign(reassign)
+		client.SetAttachProjects(attachProjects)
+
+		s := &apiServer{
+			version:          cmd.Root().Version,
+			readOnly:         readOnly,
+			pinScope:         pinScope,
+			includeUnmanaged: includeUnmanaged,
+			configData:       configData,
+		}
+		server := &http.Server{Addr: ":" + port, Handler: s.routes(), ReadHeaderTimeout: 10 * time.Second}
+
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+
+		logger.Info("Serving the REST API", "port", port, "readOnly", readOnly, "pinScope", pinScope)
+		if err = server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("api server failed: %w", err)
+		}
+		return nil
+	},
+	Example: `Serve the REST API: ` + serveCmdExamples[0] + `
+Serve a read-only API limited to a management project: ` + serveCmdExamples[1],
+}
+
+var serveCmdExamples = []string{
+	`apphub-app-creator apps serve --config config.yaml`,
+	`apphub-app-creator apps serve --management-project $mp --locations us-west1 --read-only --pin-scope --port 9090`,
+}
+
+func GetServeExample(i int) string {
+	return serveCmdExamples[i]
+}
+
+func init() {
+	var readOnly, pinScope, includeUnmanaged, reassign, attachProjects bool
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	ServeCmd.Flags().StringVarP(&port, "port", "",
+		port, "Port serving the API, defaults to the PORT environment variable or 8080")
+	ServeCmd.Flags().BoolVarP(&readOnly, "read-only", "",
+		false, "Reject the requests that change App Hub: apply and delete")
+	ServeCmd.Flags().BoolVarP(&pinScope, "pin-scope", "",
+		false, "Reject the requests for another parent, management project or locations than those of the flags")
+	ServeCmd.Flags().BoolVarP(&includeUnmanaged, "include-unmanaged", "",
+		false, "Allow changing and deleting applications that were not created by this tool")
+	ServeCmd.Flags().BoolVarP(&reassign, "reassign", "",
+		false, "Move services and workloads that are registered in another application to the generated application")
+	ServeCmd.Flags().BoolVarP(&attachProjects, "attach-projects", "",
+		false, "Attach the projects of the assets to the management project as service projects when they are not attached")
+}
+
+// maxRequestSize bounds the request body, a generate job with its rules
+const maxRequestSize = 1 << 20
+
+// apiServer serves the REST API. Requests that run a generation or delete are
+// served one at a time, since the client keeps the provenance, exclusions and
+// journal of the current run. List and describe are served concurrently.
+type apiServer struct {
+	mu               sync.Mutex
+	version          string
+	readOnly         bool
+	pinScope         bool
+	includeUnmanaged bool
+	configData       []byte
+}
+
+// apiError is an error returned with its HTTP status
+type apiError struct {
+	status int
+	err    error
+}
+
+func (e *apiError) Error() string {
+	return e.err.Error()
+}
+
+func badRequest(format string, a ...interface{}) error {
+	return &apiError{status: http.StatusBadRequest, err: fmt.Errorf(format, a...)}
+}
+
+// generateResponse is the result of generate and apply
+type generateResponse struct {
+	RunID        string                        `json:"runId,omitempty"`
+	Changes      int                           `json:"changes,omitempty"`
+	Applications []client.GeneratedApplication `json:"applications"`
+	Unmatched    []string                      `json:"unmatched,omitempty"`
+	Excluded     []client.ExcludedAsset        `json:"excluded,omitempty"`
+}
+
+// deleteResponse is the result of delete. On a partial failure, the error is
+// returned along with the result of every application.
+type deleteResponse struct {
+	RunID        string                          `json:"runId,omitempty"`
+	Snapshot     string                          `json:"snapshot"`
+	Applications []client.ApplicationDescription `json:"applications"`
+	Results      []deletionResult                `json:"results,omitempty"`
+	Error        string                          `json:"error,omitempty"`
+}
+
+// deletionResult is the outcome of deleting one application
+type deletionResult struct {
+	ID       string `json:"id"`
+	Location string `json:"location"`
+	Error    string `json:"error,omitempty"`
+}
+
+// routeKind tells handle how to serve a route
+type routeKind int
+
+const (
+	// readRoute only reads App Hub and is served concurrently
+	readRoute routeKind = iota
+	// runRoute generates with the run state of the client, one at a time
+	runRoute
+	// mutatingRoute is a runRoute that changes App Hub, rejected when read-only
+	mutatingRoute
+)
+
+// loggerKey is the context key of the request logger
+type loggerKey struct{}
+
+// requestLogger returns the logger of a request, which adds the request ID to
+// every record
+func requestLogger(r *http.Request) *slog.Logger {
+	if logger, ok := r.Context().Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return clilog.GetLogger()
+}
+
+func (s *apiServer) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("POST /v1/generate", s.handle(s.generate, runRoute))
+	mux.HandleFunc("POST /v1/plan", s.handle(s.plan, runRoute))
+	mux.HandleFunc("POST /v1/apply", s.handle(s.apply, mutatingRoute))
+	mux.HandleFunc("GET /v1/applications", s.handle(s.list, readRoute))
+	mux.HandleFunc("GET /v1/applications/{name}", s.handle(s.describe, readRoute))
+	mux.HandleFunc("DELETE /v1/applications/{name}", s.handle(s.delete, mutatingRoute))
+	return mux
+}
+
+// handle serves a request with a logger that adds the request ID to every
+// record and writes the result, or the error, as JSON. A handler may return a
+// result with its error, such as the results of a partial deletion.
+func (s *apiServer) handle(h func(r *http.Request) (interface{}, error), kind routeKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get("X-Request-Id")
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		w.Header().Set("X-Request-Id", requestID)
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+
+		if kind == mutatingRoute && s.readOnly {
+			writeAPIResponse(w, http.StatusForbidden, map[string]string{"error": "the server is read-only"})
+			return
+		}
+
+		if kind != readRoute {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+		}
+
+		logger := clilog.GetLogger().With("requestId", requestID)
+		r = r.WithContext(context.WithValue(r.Context(), loggerKey{}, logger))
+
+		start := time.Now()
+		logger.Info("Request started", "method", r.Method, "path", r.URL.Path)
+
+		result, err := h(r)
+		code := http.StatusOK
+		if err != nil {
+			code = http.StatusInternalServerError
+			var apiErr *apiError
+			var notFound *client.NotFoundError
+			if errors.As(err, &apiErr) {
+				code = apiErr.status
+			} else if errors.As(err, &notFound) {
+				code = http.StatusNotFound
+			}
+			if result == nil {
+				result = map[string]string{"error": err.Error()}
+			}
+		}
+
+		logger.Info("Request finished", "method", r.Method, "path", r.URL.Path, "status", code,
+			"duration", time.Since(start).Round(time.Millisecond).String(), "error", err)
+		writeAPIResponse(w, code, result)
+	}
+}
+
+func (s *apiServer) generate(r *http.Request) (interface{}, error) {
+	job, err := s.decodeJob(r, true)
+	if err != nil {
+		return nil, err
+	}
+	generated, unmatched, err := job.Generate(true)
+	if err != nil {
+		return nil, err
+	}
+	return generateResponse{
+		Applications: client.NewGeneratedApplications(generated),
+		Unmatched:    unmatched,
+		Excluded:     client.GetExcludedAssets(),
+	}, nil
+}
+
+func (s *apiServer) plan(r *http.Request) (interface{}, error) {
+	job, err := s.decodeJob(r, true)
+	if err != nil {
+		return nil, err
+	}
+	return client.PlanGeneration(job)
+}
+
+func (s *apiServer) apply(r *http.Request) (interface{}, error) {
+	job, err := s.decodeJob(r, false)
+	if err != nil {
+		return nil, err
+	}
+	runID, err := s.startRun()
+	if err != nil {
+		return nil, err
+	}
+	// as with delete, the changes made before a failure are returned with the
+	// error so that the run can be reversed with undo
+	generated, unmatched, err := job.Generate(false)
+	return generateResponse{
+		RunID:        runID,
+		Changes:      client.GetJournaledMutations(),
+		Applications: client.NewGeneratedApplications(generated),
+		Unmatched:    unmatched,
+	}, err
+}
+
+func (s *apiServer) list(r *http.Request) (interface{}, error) {
+	project, appLocations, err := s.getScope(r)
+	if err != nil {
+		return nil, err
+	}
+	query := r.URL.Query()
+	return client.ListApps(project, appLocations, query["name"], query["filter"])
+}
+
+func (s *apiServer) describe(r *http.Request) (interface{}, error) {
+	project, appLocations, err := s.getScope(r)
+	if err != nil {
+		return nil, err
+	}
+	enrich, _ := strconv.ParseBool(r.URL.Query().Get("enrich"))
+	return client.DescribeApp(project, r.PathValue("name"), appLocations, enrich)
+}
+
+func (s *apiServer) delete(r *http.Request) (interface{}, error) {
+	project, appLocations, err := s.getScope(r)
+	if err != nil {
+		return nil, err
+	}
+	name := r.PathValue("name")
+
+	plan, err := client.PlanDeletion(project, name, appLocations, s.includeUnmanaged)
+	if err != nil {
+		return nil, err
+	}
+	if len(plan) == 0 {
+		return nil, &client.NotFoundError{Name: name, Locations: appLocations}
+	}
+
+	runID, err := s.startRun()
+	if err != nil {
+		return nil, err
+	}
+
+	// as with the delete command, a snapshot is written before anything is deleted, named after the run
+	var appNames []string
+	for _, app := range plan {
+		appNames = append(appNames, app.Name)
+	}
+	snapshot := fmt.Sprintf("apphub-snapshot-%s-%s.json", project, runID)
+	if err = client.WriteSnapshot(project, appNames, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot, nothing was deleted: %w", err)
+	}
+	requestLogger(r).Info("Snapshot written", "snapshot", snapshot, "applications", len(appNames))
+
+	response := deleteResponse{RunID: runID, Snapshot: snapshot, Applications: plan}
+	results, err := client.DeleteApps(project, plan, client.DEFAULT_DELETION_CONCURRENCY, nil)
+	for _, result := range results {
+		deletion := deletionResult{ID: result.ID, Location: result.Location}
+		if result.Error != nil {
+			deletion.Error = result.Error.Error()
+		}
+		response.Results = append(response.Results, deletion)
+	}
+	if err != nil {
+		response.Error = err.Error()
+		return response, err
+	}
+	return response, nil
+}
+
+// decodeJob reads a generate job from the request body, defaulting the
+// parent, management project and locations to the flags of the server, and
+// resets the exclusions so the request reports its own excluded assets
+func (s *apiServer) decodeJob(r *http.Request, reportOnly bool) (*client.GenerateJob, error) {
+	job := &client.GenerateJob{}
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(job); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, &apiError{status: http.StatusRequestEntityTooLarge, err: err}
+		}
+		return nil, badRequest("invalid request body: %v", err)
+	}
+	if err := s.checkScope(job.Parent, job.ManagementProject, job.Locations); err != nil {
+		return nil, err
+	}
+	if job.Parent == "" {
+		job.Parent = parent
+	}
+	if job.ManagementProject == "" {
+		job.ManagementProject = managementProject
+	}
+	if len(job.Locations) == 0 {
+		job.Locations = locations
+	}
+	if err := job.Validate(); err != nil {
+		return nil, badRequest("invalid job: %v", err)
+	}
+
+	if err := client.SetExclusions(s.configData, nil, nil, nil, nil, nil, reportOnly); err != nil {
+		return nil, err
+	}
+	client.SetProvenance(s.version, job.Mode())
+	return job, nil
+}
+
+// getScope returns the management project and locations of a request, read
+// from the managementProject and location query parameters or the flags of
+// the server. The location all is resolved as with the commands.
+func (s *apiServer) getScope(r *http.Request) (string, []string, error) {
+	query := r.URL.Query()
+	project := query.Get("managementProject")
+	if err := s.checkScope("", project, query["location"]); err != nil {
+		return "", nil, err
+	}
+	if project == "" {
+		project = managementProject
+	}
+	if project == "" {
+		return "", nil, badRequest("managementProject is required")
+	}
+	requestLocations := query["location"]
+	if len(requestLocations) == 0 {
+		requestLocations = locations
+	}
+	if len(requestLocations) == 0 {
+		return "", nil, badRequest("at least one location is required")
+	}
+	appLocations, err := client.ResolveLocations(project, requestLocations)
+	if err != nil {
+		return "", nil, err
+	}
+	return project, appLocations, nil
+}
+
+// checkScope rejects, with --pin-scope, a request for another parent,
+// management project or locations than the flags of the server. Values that
+// are not set default to the flags.
+func (s *apiServer) checkScope(requestParent, requestProject string, requestLocations []string) error {
+	if !s.pinScope {
+		return nil
+	}
+	if requestParent != "" && requestParent != parent {
+		return &apiError{status: http.StatusForbidden, err: fmt.Errorf("parent %s is not served", requestParent)}
+	}
+	if requestProject != "" && requestProject != managementProject {
+		return &apiError{status: http.StatusForbidden, err: fmt.Errorf("management project %s is not served", requestProject)}
+	}
+	for _, location := range requestLocations {
+		if !slices.Contains(locations, location) {
+			return &apiError{status: http.StatusForbidden, err: fmt.Errorf("location %s is not served", location)}
+		}
+	}
+	return nil
+}
+
+// startRun records the changes of the request in the journal under a new run ID
+func (s *apiServer) startRun() (string, error) {
+	runID, err := newRunID()
+	if err != nil {
+		return "", err
+	}
+	client.SetJournal(journal, runID)
+	return runID, nil
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func writeAPIResponse(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
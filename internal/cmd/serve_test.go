@@ -0,0 +1,203 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"internal/clilog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIServer(t *testing.T) {
+	clilog.Init(nil)
+	managementProject, locations = "", nil
+
+	tests := []struct {
+		name     string
+		readOnly bool
+		method   string
+		path     string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "Health",
+			method:   http.MethodGet,
+			path:     "/healthz",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Delete when read-only",
+			readOnly: true,
+			method:   http.MethodDelete,
+			path:     "/v1/applications/orders?managementProject=host&location=us-west1",
+			wantCode: http.StatusForbidden,
+			wantBody: "the server is read-only",
+		},
+		{
+			name:     "Apply when read-only",
+			readOnly: true,
+			method:   http.MethodPost,
+			path:     "/v1/apply",
+			body:     `{"parent": "projects/p", "locations": ["us-west1"], "labelKey": "app"}`,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "Generate with two modes",
+			method:   http.MethodPost,
+			path:     "/v1/generate",
+			body:     `{"parent": "projects/p", "locations": ["us-west1"], "labelKey": "app", "autoDetect": true}`,
+			wantCode: http.StatusBadRequest,
+			wantBody: "exactly one generate mode is required",
+		},
+		{
+			name:     "Plan with an unknown field",
+			method:   http.MethodPost,
+			path:     "/v1/plan",
+			body:     `{"parent": "projects/p", "label": "app"}`,
+			wantCode: http.StatusBadRequest,
+			wantBody: "unknown field",
+		},
+		{
+			name:     "List without a management project",
+			method:   http.MethodGet,
+			path:     "/v1/applications?location=us-west1",
+			wantCode: http.StatusBadRequest,
+			wantBody: "managementProject is required",
+		},
+		{
+			name:     "Unknown route",
+			method:   http.MethodPut,
+			path:     "/v1/applications/orders",
+			wantCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &apiServer{readOnly: tt.readOnly}
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			s.routes().ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want %s", rec.Body.String(), tt.wantBody)
+			}
+			if tt.path != "/healthz" && tt.wantCode != http.StatusMethodNotAllowed && rec.Header().Get("X-Request-Id") == "" {
+				t.Errorf("X-Request-Id header is not set")
+			}
+		})
+	}
+}
+
+func TestAPIServerReadsDuringRun(t *testing.T) {
+	clilog.Init(nil)
+	managementProject, locations = "", nil
+
+	s := &apiServer{}
+	// a generation in progress holds the run state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	done := make(chan int)
+	go func() {
+		rec := httptest.NewRecorder()
+		s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/applications?location=us-west1", nil))
+		done <- rec.Code
+	}()
+	select {
+	case code := <-done:
+		if code != http.StatusBadRequest {
+			t.Errorf("status = %d, want %d", code, http.StatusBadRequest)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("list waited for the generation in progress")
+	}
+}
+
+func TestAPIServerPinScope(t *testing.T) {
+	clilog.Init(nil)
+	parent, managementProject, locations = "projects/host", "host", []string{"us-west1"}
+	defer func() { parent, managementProject, locations = "", "", nil }()
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "Generate for another parent",
+			method:   http.MethodPost,
+			path:     "/v1/generate",
+			body:     `{"parent": "projects/other", "labelKey": "app"}`,
+			wantCode: http.StatusForbidden,
+			wantBody: "parent projects/other is not served",
+		},
+		{
+			name:     "Plan for another management project",
+			method:   http.MethodPost,
+			path:     "/v1/plan",
+			body:     `{"managementProject": "other", "labelKey": "app"}`,
+			wantCode: http.StatusForbidden,
+			wantBody: "management project other is not served",
+		},
+		{
+			name:     "List in another location",
+			method:   http.MethodGet,
+			path:     "/v1/applications?location=us-east1",
+			wantCode: http.StatusForbidden,
+			wantBody: "location us-east1 is not served",
+		},
+		{
+			name:     "Delete in another management project",
+			method:   http.MethodDelete,
+			path:     "/v1/applications/orders?managementProject=other",
+			wantCode: http.StatusForbidden,
+			wantBody: "management project other is not served",
+		},
+		{
+			name:     "Body too large",
+			method:   http.MethodPost,
+			path:     "/v1/generate",
+			body:     `{"labelKey": "` + strings.Repeat("a", maxRequestSize) + `"}`,
+			wantCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &apiServer{pinScope: true}
+			rec := httptest.NewRecorder()
+			s.routes().ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want %s", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
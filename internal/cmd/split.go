@@ -0,0 +1,134 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"internal/client"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// SplitAppCmd to split an application
+var SplitAppCmd = &cobra.Command{
+	Use:   "split",
+	Short: "Split an App Hub Application by label",
+	Long: "Move the services and workloads of an App Hub Application into one application per value " +
+		"of a CAIS label of the underlying resources, and delete the application if it is emptied",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		name := GetStringParam(cmd.Flag("name"))
+		byLabel := GetStringParam(cmd.Flag("by-label"))
+
+		if managementProject == "" {
+			return fmt.Errorf("management project is a required field")
+		}
+		if len(locations) == 0 {
+			return fmt.Errorf("at least one location is required")
+		}
+		if name == "" {
+			return fmt.Errorf("name is a required field")
+		}
+		if byLabel == "" {
+			return fmt.Errorf("by-label is a required field")
+		}
+		return
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		cmd.SilenceUsage = true
+
+		name := GetStringParam(cmd.Flag("name"))
+		byLabel := GetStringParam(cmd.Flag("by-label"))
+		attributes := GetStringParam(cmd.Flag("attributes"))
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		includeUnmanaged, _ := cmd.Flags().GetBool("include-unmanaged")
+		yes, _ := cmd.Flags().GetBool("yes")
+		maxDeletions, _ := cmd.Flags().GetInt("max-deletions")
+		snapshot := GetStringParam(cmd.Flag("snapshot"))
+
+		var attributesData []byte
+		if attributes != "" {
+			if attributesData, err = os.ReadFile(attributes); err != nil {
+				return err
+			}
+		}
+
+		client.SetProvenance(cmd.Root().Version, "split")
+		client.SetIncludeUnmanaged(includeUnmanaged)
+
+		plan, err := client.PlanSplit(managementProject, name, locations, byLabel, attributesData, includeUnmanaged)
+		if err != nil {
+			return err
+		}
+
+		if len(plan.Moves) == 0 {
+			fmt.Printf("No services or workloads of %s have the label %s\n", name, byLabel)
+			return nil
+		}
+
+		PrintReorganizationPlan(plan)
+
+		if dryRun {
+			return nil
+		}
+		if len(plan.Delete) > 0 {
+			if err = checkPlanDeletions(managementProject, len(plan.Delete), maxDeletions, yes); err != nil {
+				return err
+			}
+			if err = writeSnapshot(snapshot, plan.Delete); err != nil {
+				return err
+			}
+		}
+		err = client.ApplyPlan(managementProject, plan)
+		printRunID()
+		return err
+	},
+	Example: `Split an application by the value of the team label: ` + splitAppCmdExamples[0] + `
+Preview the split: ` + splitAppCmdExamples[1],
+}
+
+var splitAppCmdExamples = []string{
+	`apphub-app-creator apps split --name $name --by-label team --management-project $project --locations us-west1`,
+	`apphub-app-creator apps split --name $name --by-label team --management-project $project --locations us-west1 --dry-run`,
+}
+
+func GetSplitAppExample(i int) string {
+	return splitAppCmdExamples[i]
+}
+
+func init() {
+	var name, byLabel, attributes, snapshot string
+	var dryRun, includeUnmanaged, yes bool
+	var maxDeletions int
+
+	SplitAppCmd.Flags().StringVarP(&name, "name", "",
+		"", "Name of the App Hub Application to split")
+	SplitAppCmd.Flags().StringVarP(&byLabel, "by-label", "",
+		"", "CAIS label key of the underlying resources; one application is created per label value")
+	SplitAppCmd.Flags().StringVarP(&attributes, "attributes", "",
+		"", "Path to a json file containing App Hub attributes for the new applications. "+
+			"Defaults to the attributes of the application being split")
+	SplitAppCmd.Flags().StringVarP(&snapshot, "snapshot", "",
+		"", "Path of the snapshot written before deleting emptied applications, used by apps restore. "+
+			"Defaults to apphub-snapshot-{project}-{time}.json in the current directory")
+	SplitAppCmd.Flags().BoolVarP(&dryRun, "dry-run", "",
+		false, "Print the plan without changing any application")
+	SplitAppCmd.Flags().BoolVarP(&yes, "yes", "",
+		false, "Skip the confirmation prompt before deleting the emptied applications")
+	SplitAppCmd.Flags().IntVarP(&maxDeletions, "max-deletions", "",
+		0, "Refuse to proceed if more than this number of applications would be deleted. 0 means no limit")
+	SplitAppCmd.Flags().BoolVarP(&includeUnmanaged, "include-unmanaged", "",
+		false, "Allow splitting an application that was not created by this tool")
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"internal/client"
+
+	"github.com/spf13/cobra"
+)
+
+// UndoCmd to reverse the changes of a run
+var UndoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Reverse the App Hub changes made by a run",
+	Long: "Delete the applications, services and workloads created by a run, in reverse order, using the " +
+		"journal. Resources modified since the run are left in place. Deletions are not reversed, use apps restore",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if GetStringParam(cmd.Flag("run")) == "" {
+			return fmt.Errorf("run is a required field")
+		}
+		if journal == "" {
+			return fmt.Errorf("journal is a required field")
+		}
+		return
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		cmd.SilenceUsage = true
+
+		runID := GetStringParam(cmd.Flag("run"))
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		snapshot := getSnapshotPath(GetStringParam(cmd.Flag("snapshot")), runID)
+
+		results, err := client.UndoRun(journal, runID, snapshot, dryRun)
+		if err != nil {
+			return err
+		}
+
+		PrintUndoResults(results, dryRun)
+		return nil
+	},
+	Example: `Reverse the changes of a generate run: ` + undoCmdExamples[0] + `
+Preview the changes that would be reversed: ` + undoCmdExamples[1],
+}
+
+var undoCmdExamples = []string{
+	`apphub-app-creator apps undo --run 20250101-120000-a1b2c3`,
+	`apphub-app-creator apps undo --run 20250101-120000-a1b2c3 --journal /tmp/apphub-journal.jsonl --dry-run`,
+}
+
+func GetUndoExample(i int) string {
+	return undoCmdExamples[i]
+}
+
+func init() {
+	var runID, snapshot string
+	var dryRun bool
+
+	UndoCmd.Flags().StringVarP(&runID, "run", "",
+		"", "ID of the run to reverse, printed at the end of the run and recorded in the journal")
+	UndoCmd.Flags().StringVarP(&snapshot, "snapshot", "",
+		"", "Path of the snapshot of the affected applications written before undoing, used by apps restore. "+
+			"Defaults to apphub-snapshot-{run}-{time}.json in the current directory")
+	UndoCmd.Flags().BoolVarP(&dryRun, "dry-run", "",
+		false, "Report the changes that would be reversed without deleting anything")
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"internal/client"
+
+	"github.com/spf13/cobra"
+)
+
+// UnregisteredCmd to list discovered services and workloads that belong to no application
+var UnregisteredCmd = &cobra.Command{
+	Use:   "unregistered",
+	Short: "List discovered services and workloads not registered in any application",
+	Long: "List the discovered services and workloads in the management project that do not " +
+		"belong to any App Hub Application, with the project and labels of the underlying resource",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if managementProject == "" {
+			return fmt.Errorf("management project is a required field")
+		}
+		if len(locations) == 0 {
+			return fmt.Errorf("at least one location is required")
+		}
+		return
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		cmd.SilenceUsage = true
+
+		appLocations, err := client.ResolveLocations(managementProject, locations)
+		if err != nil {
+			return err
+		}
+
+		unregistered, err := client.ListUnregistered(managementProject, appLocations)
+		if err != nil {
+			return err
+		}
+
+		PrintUnregisteredResources(unregistered)
+		return nil
+	},
+	Example: `List discovered resources not registered in any application: ` + unregisteredCmdExamples[0],
+}
+
+var unregisteredCmdExamples = []string{
+	`apphub-app-creator apps unregistered --management-project $project --locations us-west1 --locations global`,
+}
+
+func GetUnregisteredExample(i int) string {
+	return unregisteredCmdExamples[i]
+}
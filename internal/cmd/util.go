@@ -1,10 +1,17 @@
 package cmd
 
 import (
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"internal/client"
 	"os"
+	"slices"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/pflag"
 )
@@ -84,3 +91,383 @@ func PrintGeneratedApplication(generatedApplications map[string][]string) {
 		// fmt.Fprintln(w, "--------\t---------------\t-------------\t-----------")
 	}
 }
+
+func PrintUnmatchedAssets(unmatchedAssets []string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "UNMATCHED RESOURCE URI")
+	fmt.Fprintln(w, "----------------------")
+	for _, assetName := range unmatchedAssets {
+		fmt.Fprintln(w, assetName)
+	}
+}
+
+func PrintUnmanagedApplications(appNames []string) {
+	if len(appNames) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "SKIPPED APP NAME\tREASON")
+	fmt.Fprintln(w, "----------------\t------")
+	for _, appName := range appNames {
+		fmt.Fprintf(w, "%s\tnot created by apphub-app-creator, adopt it with apps adopt or use --include-unmanaged\n", appName)
+	}
+}
+
+func PrintExcludedAssets(excludedAssets []client.ExcludedAsset) {
+	if len(excludedAssets) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "EXCLUDED RESOURCE URI\tREASON")
+	fmt.Fprintln(w, "---------------------\t------")
+	for _, excludedAsset := range excludedAssets {
+		fmt.Fprintf(w, "%s\t%s\n", excludedAsset.Name, excludedAsset.Reason)
+	}
+}
+
+func PrintUnregisteredResources(unregistered []client.UnregisteredResource) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "APP HUB TYPE\tRESOURCE URI\tPROJECT\tLOCATION\tLABELS")
+	fmt.Fprintln(w, "------------\t------------\t-------\t--------\t------")
+	for _, r := range unregistered {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.AppHubType, r.URI, r.Project, r.Location, formatLabels(r.Labels))
+	}
+}
+
+// formatLabels returns labels as a sorted, comma separated list of key=value
+func formatLabels(labels map[string]string) string {
+	var pairs []string
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func PrintCoverage(report *client.CoverageReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "SCOPE\tNAME\tREGISTERED\tUNREGISTERED\tTOTAL\tCOVERAGE")
+	fmt.Fprintln(w, "-----\t----\t----------\t------------\t-----\t--------")
+	printCoverageCount(w, "overall", report.ManagementProject, report.Overall)
+	for _, scope := range []struct {
+		name   string
+		counts map[string]*client.CoverageCount
+	}{
+		{"project", report.Projects},
+		{"location", report.Locations},
+		{"resource type", report.ResourceTypes},
+	} {
+		keys := make([]string, 0, len(scope.counts))
+		for key := range scope.counts {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			printCoverageCount(w, scope.name, key, scope.counts[key])
+		}
+	}
+}
+
+func printCoverageCount(w *tabwriter.Writer, scope, name string, c *client.CoverageCount) {
+	fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%.2f%%\n", scope, name, c.Registered, c.Unregistered, c.Total, c.Coverage)
+}
+
+func PrintJSON(v interface{}) error {
+	payload, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %w", err)
+	}
+	fmt.Println(string(payload))
+	return nil
+}
+
+var applicationColumns = []string{
+	"ID", "DISPLAY NAME", "LOCATION", "SCOPE", "MANAGED", "CRITICALITY", "ENVIRONMENT",
+	"DEVELOPER OWNERS", "OPERATOR OWNERS", "BUSINESS OWNERS", "SERVICES", "WORKLOADS",
+}
+
+func getApplicationRow(a client.ApplicationSummary) []string {
+	return []string{
+		a.ID, a.DisplayName, a.Location, a.Scope, fmt.Sprint(a.Managed), a.Criticality, a.Environment,
+		strings.Join(a.DeveloperOwners, ";"), strings.Join(a.OperatorOwners, ";"), strings.Join(a.BusinessOwners, ";"),
+		fmt.Sprint(a.Services), fmt.Sprint(a.Workloads),
+	}
+}
+
+func PrintApplications(applications []client.ApplicationSummary) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join(applicationColumns, "\t"))
+	var separators []string
+	for _, column := range applicationColumns {
+		separators = append(separators, strings.Repeat("-", len(column)))
+	}
+	fmt.Fprintln(w, strings.Join(separators, "\t"))
+	for _, a := range applications {
+		fmt.Fprintln(w, strings.Join(getApplicationRow(a), "\t"))
+	}
+}
+
+func PrintApplicationsCSV(applications []client.ApplicationSummary) error {
+	w := csv.NewWriter(os.Stdout)
+	if err := w.Write(applicationColumns); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	for _, a := range applications {
+		if err := w.Write(getApplicationRow(a)); err != nil {
+			return fmt.Errorf("failed to write csv: %w", err)
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func PrintApplicationDescription(description *client.ApplicationDescription, enrich bool) {
+	PrintApplications([]client.ApplicationSummary{description.ApplicationSummary})
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+	defer w.Flush()
+
+	columns := "ID\tAPP HUB TYPE\tRESOURCE URI\tLOCATION\tCRITICALITY\tENVIRONMENT"
+	separators := "--\t------------\t------------\t--------\t-----------\t-----------"
+	if enrich {
+		columns += "\tLABELS\tTAGS"
+		separators += "\t------\t----"
+	}
+	fmt.Fprintln(w, columns)
+	fmt.Fprintln(w, separators)
+	for _, m := range description.Members {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s", m.ID, m.AppHubType, m.URI, m.Location, m.Criticality, m.Environment)
+		if enrich {
+			fmt.Fprintf(w, "\t%s\t%s", formatLabels(m.Labels), formatLabels(m.Tags))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func PrintDeletionPlan(plan []client.ApplicationDescription) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+	defer w.Flush()
+
+	var services, workloads int
+	fmt.Fprintln(w, "APP NAME\tLOCATION\tAPP HUB TYPE\tRESOURCE URI")
+	fmt.Fprintln(w, "--------\t--------\t------------\t------------")
+	for _, app := range plan {
+		fmt.Fprintf(w, "%s\t%s\tapplication\t%s\n", app.ID, app.Location, app.Name)
+		for _, m := range app.Members {
+			fmt.Fprintf(w, "\t\t%s\t%s\n", m.AppHubType, m.URI)
+		}
+		services += app.Services
+		workloads += app.Workloads
+	}
+	fmt.Fprintf(w, "\nTotal: %d applications, %d services, %d workloads\n", len(plan), services, workloads)
+}
+
+func PrintDetachedMembers(members []client.ApplicationMember, dryRun bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+	defer w.Flush()
+
+	status := "DETACHED"
+	if dryRun {
+		status = "WOULD DETACH"
+	}
+	fmt.Fprintln(w, "ID\tAPP HUB TYPE\tRESOURCE URI\tSTATUS")
+	fmt.Fprintln(w, "--\t------------\t------------\t------")
+	for _, m := range members {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", m.ID, m.AppHubType, m.URI, status)
+	}
+}
+
+func PrintMovedMembers(members []client.MovedMember, dryRun bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+	defer w.Flush()
+
+	status := "MOVED"
+	if dryRun {
+		status = "WOULD MOVE"
+	}
+	fmt.Fprintln(w, "ID\tAPP HUB TYPE\tRESOURCE URI\tFROM\tTO\tSTATUS")
+	fmt.Fprintln(w, "--\t------------\t------------\t----\t--\t------")
+	for _, m := range members {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", m.ID, m.AppHubType, m.URI,
+			m.From[strings.LastIndex(m.From, "/")+1:], m.To[strings.LastIndex(m.To, "/")+1:], status)
+	}
+}
+
+func PrintReorganizationPlan(plan *client.ReorganizationPlan) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "ACTION\tAPP NAME\tAPP HUB TYPE\tRESOURCE URI")
+	fmt.Fprintln(w, "------\t--------\t------------\t------------")
+	for _, app := range plan.Create {
+		fmt.Fprintf(w, "create\t%s\tapplication\t%s\n", app.ID, app.Location)
+	}
+	for _, m := range plan.Moves {
+		fmt.Fprintf(w, "move\t%s -> %s\t%s\t%s\n", m.From[strings.LastIndex(m.From, "/")+1:],
+			m.To[strings.LastIndex(m.To, "/")+1:], m.AppHubType, m.URI)
+	}
+	for _, appName := range plan.Delete {
+		fmt.Fprintf(w, "delete\t%s\tapplication\t%s\n", appName[strings.LastIndex(appName, "/")+1:], appName)
+	}
+	fmt.Fprintf(w, "\nTotal: %d applications created, %d members moved, %d applications deleted\n",
+		len(plan.Create), len(plan.Moves), len(plan.Delete))
+}
+
+func PrintDeletionFailures(results []client.DeletionResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+	defer w.Flush()
+
+	var failed int
+	fmt.Fprintln(w, "\nAPP NAME\tLOCATION\tERROR")
+	fmt.Fprintln(w, "--------\t--------\t-----")
+	for _, result := range results {
+		if result.Error != nil {
+			fmt.Fprintf(w, "%s\t%s\t%v\n", result.ID, result.Location, result.Error)
+			failed++
+		}
+	}
+	fmt.Fprintf(w, "\n%d of %d applications could not be deleted\n", failed, len(results))
+}
+
+// writeSnapshot saves applications before they are deleted so that they can be
+// restored with apps restore
+func writeSnapshot(path string, appNames []string) error {
+	path = getSnapshotPath(path, managementProject)
+	if err := client.WriteSnapshot(managementProject, appNames, path); err != nil {
+		return fmt.Errorf("failed to write snapshot, nothing was deleted: %w", err)
+	}
+	fmt.Printf("Snapshot of %d applications written to %s\n", len(appNames), path)
+	return nil
+}
+
+// getSnapshotPath returns the snapshot path, defaulting to a file in the
+// current directory named after the given project or run
+func getSnapshotPath(path, name string) string {
+	if path != "" {
+		return path
+	}
+	return fmt.Sprintf("apphub-snapshot-%s-%s.json", name, time.Now().UTC().Format("20060102-150405"))
+}
+
+func PrintRestoreReport(report *client.RestoreReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+	defer w.Flush()
+
+	var missing int
+	fmt.Fprintln(w, "APP NAME\tAPP HUB TYPE\tRESOURCE URI\tSTATUS\tERROR")
+	fmt.Fprintln(w, "--------\t------------\t------------\t------\t-----")
+	for _, appName := range report.Created {
+		fmt.Fprintf(w, "%s\tapplication\t%s\tCREATED\t\n", appName[strings.LastIndex(appName, "/")+1:], appName)
+	}
+	for _, appName := range report.Existing {
+		fmt.Fprintf(w, "%s\tapplication\t%s\tEXISTS\t\n", appName[strings.LastIndex(appName, "/")+1:], appName)
+	}
+	for _, m := range report.Members {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", m.Application[strings.LastIndex(m.Application, "/")+1:],
+			m.AppHubType, m.URI, m.Status, m.Error)
+		if m.Status == "MISSING" {
+			missing++
+		}
+	}
+	fmt.Fprintf(w, "\nTotal: %d applications created, %d members, %d no longer exist\n",
+		len(report.Created), len(report.Members), missing)
+}
+
+// newRunID returns an ID for the changes made by one invocation of the tool
+func newRunID() (string, error) {
+	b := make([]byte, 3)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate run ID: %w", err)
+	}
+	return time.Now().UTC().Format("20060102-150405") + "-" + hex.EncodeToString(b), nil
+}
+
+// printRunID prints how to reverse the changes recorded in the journal
+func printRunID() {
+	if n := client.GetJournaledMutations(); n > 0 && journal != "" {
+		fmt.Printf("Run %s recorded %d changes in %s, reverse them with apps undo --run %s\n",
+			client.GetRunID(), n, journal, client.GetRunID())
+	}
+}
+
+func PrintUndoResults(results []client.UndoResult, dryRun bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+	defer w.Flush()
+
+	var undone int
+	fmt.Fprintln(w, "ACTION\tRESOURCE\tSTATUS\tERROR")
+	fmt.Fprintln(w, "------\t--------\t------\t-----")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Action, r.Resource, r.Status, r.Error)
+		if r.Status == "UNDONE" {
+			undone++
+		}
+	}
+	if dryRun {
+		fmt.Fprintf(w, "\nTotal: %d of %d changes would be reversed\n", undone, len(results))
+	} else {
+		fmt.Fprintf(w, "\nTotal: %d of %d changes reversed\n", undone, len(results))
+	}
+}
+
+func PrintServiceProjects(serviceProjects []client.ServiceProject, dryRun bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+	defer w.Flush()
+
+	var unattached int
+	fmt.Fprintln(w, "PROJECT\tASSETS\tSTATUS\tERROR")
+	fmt.Fprintln(w, "-------\t------\t------\t-----")
+	for _, p := range serviceProjects {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", p.Project, p.Assets, p.Status, p.Error)
+		if p.Status != "ALREADY_ATTACHED" && p.Status != "ATTACHED" {
+			unattached++
+		}
+	}
+	if dryRun {
+		fmt.Fprintf(w, "\nTotal: %d projects, %d not attached\n", len(serviceProjects), unattached)
+	} else {
+		fmt.Fprintf(w, "\nTotal: %d projects, %d could not be attached\n", len(serviceProjects), unattached)
+	}
+}
+
+func PrintDoctorChecks(checks []client.DoctorCheck) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+
+	var passed int
+	fmt.Fprintln(w, "STATUS\tCHECK\tDETAIL")
+	fmt.Fprintln(w, "------\t-----\t------")
+	for _, c := range checks {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", c.Status, c.Name, c.Detail)
+		if c.Status == "PASS" {
+			passed++
+		}
+	}
+	fmt.Fprintf(w, "\nTotal: %d of %d checks passed\n", passed, len(checks))
+	w.Flush()
+
+	var remediations []string
+	for _, c := range checks {
+		if c.Remediation != "" {
+			remediations = append(remediations, fmt.Sprintf("  %s: %s", c.Name, c.Remediation))
+		}
+	}
+	if len(remediations) > 0 {
+		fmt.Println("\nRemediation:")
+		fmt.Println(strings.Join(remediations, "\n"))
+	}
+}
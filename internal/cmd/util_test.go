@@ -55,6 +55,15 @@ func TestGetStringParam(t *testing.T) {
 	}
 }
 
+func TestFormatLabels(t *testing.T) {
+	if got := formatLabels(map[string]string{"env": "prod", "appid": "shop"}); got != "appid=shop,env=prod" {
+		t.Errorf("formatLabels() = %v, want appid=shop,env=prod", got)
+	}
+	if got := formatLabels(nil); got != "" {
+		t.Errorf("formatLabels() = %v, want empty", got)
+	}
+}
+
 type stringValue string
 
 func (s *stringValue) Set(val string) error {
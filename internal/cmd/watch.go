@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"internal/client"
+	"internal/clilog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// WatchCmd to keep App Hub applications in sync with the assets
+var WatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Periodically re-run a set of generate jobs and apply the changes",
+	Long: "Run as a long-lived process that re-runs the generate jobs of a watch file every interval, " +
+		"creating applications and registering the services and workloads that are new since the last iteration. " +
+		"Serves /healthz and /status on the port for liveness probes and monitoring",
+	Args: func(cmd *cobra.Command, args []string) (err error) {
+		if GetStringParam(cmd.Flag("jobs")) == "" {
+			return fmt.Errorf("jobs is a required field")
+		}
+		return
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		cmd.SilenceUsage = true
+
+		jobs := GetStringParam(cmd.Flag("jobs"))
+		port := GetStringParam(cmd.Flag("port"))
+		includeUnmanaged, _ := cmd.Flags().GetBool("include-unmanaged")
+		reassign, _ := cmd.Flags().GetBool("reassign")
+		attachProjects, _ := cmd.Flags().GetBool("attach-projects")
+
+		logger := clilog.GetLogger()
+
+		jobsData, err := os.ReadFile(jobs)
+		if err != nil {
+			return err
+		}
+		config, err := client.ParseWatchConfig(jobsData)
+		if err != nil {
+			return err
+		}
+
+		var configData []byte
+		if configFile != "" {
+			if configData, err = os.ReadFile(configFile); err != nil {
+				return err
+			}
+		}
+		if err = client.SetAssetTypeRegistry(configData); err != nil {
+			return err
+		}
+		if err = client.SetExclusions(configData, nil, nil, nil, nil, nil, false); err != nil {
+			return err
+		}
+
+		client.SetProvenance(cmd.Root().Version, "")
+		client.SetIncludeUnmanaged(includeUnmanaged)
+		client.SetReassign(reassign)
+		client.SetAttachProjects(attachProjects)
+
+		watcher := client.NewWatcher(config, journal, newRunID)
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+			if !watcher.Status().Healthy {
+				http.Error(w, "unhealthy", http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprintln(w, "ok")
+		})
+		mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(watcher.Status())
+		})
+		server := &http.Server{Addr: ":" + port, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		serverErr := make(chan error, 1)
+		go func() {
+			logger.Info("Serving health and status", "port", port)
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErr <- err
+				stop()
+			}
+		}()
+
+		watcher.Run(ctx)
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err = server.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("failed to shut down the status server: %w", err)
+		}
+		select {
+		case err = <-serverErr:
+			return fmt.Errorf("status server failed: %w", err)
+		default:
+		}
+		return nil
+	},
+	Example: `Watch the jobs of a file: ` + watchCmdExamples[0] + `
+Watch with a custom port and exclusions: ` + watchCmdExamples[1],
+}
+
+var watchCmdExamples = []string{
+	`apphub-app-creator apps watch --jobs watch.yaml`,
+	`apphub-app-creator apps watch --jobs watch.yaml --config config.yaml --port 9090 --attach-projects`,
+}
+
+func GetWatchExample(i int) string {
+	return watchCmdExamples[i]
+}
+
+func init() {
+	var jobs string
+	var includeUnmanaged, reassign, attachProjects bool
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	WatchCmd.Flags().StringVarP(&jobs, "jobs", "",
+		"", "Path to a YAML watch file with the interval and the generate jobs to run")
+	WatchCmd.Flags().StringVarP(&port, "port", "",
+		port, "Port serving /healthz and /status, defaults to the PORT environment variable or 8080")
+	WatchCmd.Flags().BoolVarP(&includeUnmanaged, "include-unmanaged", "",
+		false, "Allow registering services and workloads in existing applications that were not created by this tool")
+	WatchCmd.Flags().BoolVarP(&reassign, "reassign", "",
+		false, "Move services and workloads that are registered in another application to the generated application")
+	WatchCmd.Flags().BoolVarP(&attachProjects, "attach-projects", "",
+		false, "Attach the projects of the assets to the management project as service projects")
+}